@@ -1,6 +1,10 @@
 package runetrie_test
 
 import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
 	"testing"
@@ -11,6 +15,8 @@ import (
 var (
 	targetStrings []string
 	searchStrings []string
+	dictionary    []string
+	urlPrefixes   []string
 )
 
 func init() {
@@ -43,6 +49,33 @@ func init() {
 		}
 	}
 	sort.Strings(searchStrings)
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		r := make([]rune, 4+rnd.Intn(12))
+		for j := range r {
+			r[j] = rune('a' + rnd.Intn(26))
+		}
+		dictionary = append(dictionary, string(r))
+	}
+
+	for _, host := range []string{"https://example.com", "https://api.example.com", "https://static.example.net"} {
+		for _, path := range []string{"/", "/v1/users/", "/v1/users/settings/", "/v1/organizations/", "/v2/organizations/members/", "/assets/images/"} {
+			urlPrefixes = append(urlPrefixes, host+path)
+		}
+	}
+}
+
+// heapSize returns the size of the heap retained by the value built by the given function.
+func heapSize(build func() any) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	v := build()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(v)
+	return after.HeapAlloc - before.HeapAlloc
 }
 
 func BenchmarkStringsHasPrefixShortestMatch(b *testing.B) {
@@ -124,3 +157,215 @@ func BenchmarkTrieLongestMatchPrefixOf(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkTrieAllPrefixesOf(b *testing.B) {
+	trie := runetrie.NewTrie(targetStrings...)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range searchStrings {
+			for range trie.AllPrefixesOf(s) {
+			}
+		}
+	}
+}
+
+func BenchmarkTrieScanEachOffset(b *testing.B) {
+	trie := runetrie.NewTrie(targetStrings...)
+	text := strings.Join(searchStrings, " ")
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for start := range text {
+			for range trie.AllPrefixesOf(text[start:]) {
+			}
+		}
+	}
+}
+
+func BenchmarkMatcherMatches(b *testing.B) {
+	matcher := runetrie.NewTrie(targetStrings...).Compile()
+	text := strings.Join(searchStrings, " ")
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for range matcher.Matches(text) {
+		}
+	}
+}
+
+func BenchmarkFrozenTrieMatchAny(b *testing.B) {
+	trie := runetrie.NewTrie(targetStrings...).Freeze()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range searchStrings {
+			trie.MatchAny(s)
+		}
+	}
+}
+
+func BenchmarkFrozenTrieMatchAnyPrefixOf(b *testing.B) {
+	trie := runetrie.NewTrie(targetStrings...).Freeze()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range searchStrings {
+			trie.MatchAnyPrefixOf(s)
+		}
+	}
+}
+
+func BenchmarkFrozenTrieMatchPrefixOf(b *testing.B) {
+	trie := runetrie.NewTrie(targetStrings...).Freeze()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range searchStrings {
+			trie.MatchPrefixOf(s)
+		}
+	}
+}
+
+func BenchmarkFrozenTrieLongestMatchPrefixOf(b *testing.B) {
+	trie := runetrie.NewTrie(targetStrings...).Freeze()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range searchStrings {
+			trie.LongestMatchPrefixOf(s)
+		}
+	}
+}
+
+func BenchmarkTrieURLPrefixes(b *testing.B) {
+	trie := runetrie.NewTrie(urlPrefixes...)
+	urls := make([]string, len(urlPrefixes))
+	for i, s := range urlPrefixes {
+		urls[i] = s + "12345"
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range urls {
+			trie.LongestMatchPrefixOf(s)
+		}
+	}
+}
+
+func BenchmarkTrieURLPrefixesMatchAny(b *testing.B) {
+	trie := runetrie.NewTrie(urlPrefixes...)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range urlPrefixes {
+			trie.MatchAny(s)
+		}
+	}
+}
+
+func BenchmarkTrieURLPrefixesMatchPrefixOf(b *testing.B) {
+	trie := runetrie.NewTrie(urlPrefixes...)
+	urls := make([]string, len(urlPrefixes))
+	for i, s := range urlPrefixes {
+		urls[i] = s + "12345"
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range urls {
+			trie.MatchPrefixOf(s)
+		}
+	}
+}
+
+func BenchmarkTrieDictionary(b *testing.B) {
+	size := heapSize(func() any { return runetrie.NewTrie(dictionary...) })
+	trie := runetrie.NewTrie(dictionary...)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range dictionary[:1000] {
+			trie.LongestMatchPrefixOf(s)
+		}
+	}
+	b.ReportMetric(float64(size), "heap-bytes")
+}
+
+func BenchmarkDAWGLongestMatchPrefixOf(b *testing.B) {
+	trie := runetrie.NewTrie(targetStrings...).Minimize()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range searchStrings {
+			trie.LongestMatchPrefixOf(s)
+		}
+	}
+}
+
+func BenchmarkDAWGDictionary(b *testing.B) {
+	trie := runetrie.NewTrie(dictionary...)
+	size := heapSize(func() any { return trie.Minimize() })
+	dawg := trie.Minimize()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range dictionary[:1000] {
+			dawg.LongestMatchPrefixOf(s)
+		}
+	}
+	b.ReportMetric(float64(size), "heap-bytes")
+}
+
+func BenchmarkBuilderDictionary(b *testing.B) {
+	sorted := make([]string, len(dictionary))
+	copy(sorted, dictionary)
+	sort.Strings(sorted)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		builder := runetrie.NewBuilder[string]()
+		for _, s := range sorted {
+			_ = builder.Insert(s)
+		}
+		builder.Finish()
+	}
+}
+
+func BenchmarkFrozenTrieDictionary(b *testing.B) {
+	trie := runetrie.NewTrie(dictionary...)
+	size := heapSize(func() any { return trie.Freeze() })
+	frozen := trie.Freeze()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, s := range dictionary[:1000] {
+			frozen.LongestMatchPrefixOf(s)
+		}
+	}
+	b.ReportMetric(float64(size), "heap-bytes")
+}
+
+func BenchmarkLoadMapped(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "trie.bin")
+	f, err := os.Create(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if _, err := runetrie.NewTrie(dictionary...).Freeze().WriteTo(f); err != nil {
+		b.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		mt, err := runetrie.LoadMapped[string](path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		mt.Close()
+	}
+}
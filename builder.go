@@ -0,0 +1,84 @@
+package runetrie
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+)
+
+// ErrUnsortedEntry is returned by Builder when the strings are not inserted in sorted order.
+var ErrUnsortedEntry = errors.New("unsorted entry")
+
+// Builder builds a DAWG from the strings inserted in sorted order.
+// Unlike NewTrie and Trie.Minimize, it does not hold all the strings as a Trie,
+// and it merges the equivalent states incrementally, so the memory usage is bounded by the size of the DAWG.
+type Builder[T ~string] struct {
+	b    *dawgBuilder
+	l    struct{ max, min int }
+	n    int
+	last string // the last folded string
+}
+
+// NewBuilder creates a new Builder for a case sensitive DAWG.
+// The strings must be inserted in lexicographic order.
+func NewBuilder[T ~string]() *Builder[T] {
+	return &Builder[T]{b: newDAWGBuilder(false)}
+}
+
+// NewCaseInsensitiveBuilder creates a new Builder for a case insensitive DAWG.
+// The strings must be inserted in lexicographic order of their case folded forms, which are the upper cases for the most, as in the order of Trie.All.
+func NewCaseInsensitiveBuilder[T ~string]() *Builder[T] {
+	return &Builder[T]{b: newDAWGBuilder(true)}
+}
+
+// Insert adds the string to the Builder.
+// If the string is ordered before the last inserted one, it returns ErrUnsortedEntry.
+// The same string as the last one is ignored, but in case insensitive mode,
+// a string which differs from the last one only in case returns *ConflictError.
+func (b *Builder[T]) Insert(s T) error {
+	folded := foldString(string(s), b.b.i)
+	if b.n != 0 {
+		switch c := strings.Compare(folded, b.last); {
+		case c < 0:
+			return ErrUnsortedEntry
+		case c == 0:
+			if !b.b.i {
+				return nil
+			}
+			if last := T(b.b.keys.String()[b.b.offs[b.n-1]:]); last != s {
+				return &ConflictError[T]{Existing: last, New: s}
+			}
+			return nil
+		}
+	}
+
+	lo, hi := foldedLen(string(s), b.b.i)
+	if b.n == 0 {
+		b.l.min, b.l.max = lo, hi
+	} else {
+		b.l.min, b.l.max = min(b.l.min, lo), max(b.l.max, hi)
+	}
+	b.b.insert(folded, string(s))
+	b.last = folded
+	b.n++
+	return nil
+}
+
+// InsertFrom adds the strings scanned by the given Scanner, such as the lines of a sorted word list file.
+// It stops at the first error and returns it.
+func (b *Builder[T]) InsertFrom(sc *bufio.Scanner) error {
+	for sc.Scan() {
+		if err := b.Insert(T(sc.Text())); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// Finish returns the DAWG of the inserted strings.
+// The Builder must not be used after Finish.
+func (b *Builder[T]) Finish() *DAWG[T] {
+	d := buildDAWG[T](b.b)
+	d.l = b.l
+	return d
+}
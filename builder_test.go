@@ -0,0 +1,155 @@
+package runetrie_test
+
+import (
+	"bufio"
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/karupanerura/runetrie"
+)
+
+func TestBuilder_Insert(t *testing.T) {
+	tests := []struct {
+		name            string
+		caseInsensitive bool
+		set             []string
+		wantErr         error
+	}{
+		{
+			name: "Sorted",
+			set:  []string{"", "A", "AB", "B", "a"},
+		},
+		{
+			name: "Duplicate",
+			set:  []string{"A", "A", "B"},
+		},
+		{
+			name:    "Unsorted",
+			set:     []string{"A", "C", "B"},
+			wantErr: runetrie.ErrUnsortedEntry,
+		},
+		{
+			name:            "CaseInsensitiveSorted",
+			caseInsensitive: true,
+			set:             []string{"a", "B", "c"},
+		},
+		{
+			name:            "CaseInsensitiveUnsorted",
+			caseInsensitive: true,
+			set:             []string{"a", "C", "B"},
+			wantErr:         runetrie.ErrUnsortedEntry,
+		},
+		{
+			name:            "CaseInsensitiveConflict",
+			caseInsensitive: true,
+			set:             []string{"Foo", "FOO"},
+			wantErr:         runetrie.ErrConflictEntry,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			b := runetrie.NewBuilder[string]()
+			if tt.caseInsensitive {
+				b = runetrie.NewCaseInsensitiveBuilder[string]()
+			}
+
+			var err error
+			for _, s := range tt.set {
+				if err = b.Insert(s); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestBuilder_InsertFrom(t *testing.T) {
+	b := runetrie.NewBuilder[string]()
+	if err := b.InsertFrom(bufio.NewScanner(strings.NewReader("talk\ntalked\ntalking\nwalk\n"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := b.Finish()
+	if d.Len() != 4 {
+		t.Errorf("DAWG.Len() = %d, want 4", d.Len())
+	}
+	if s, ok := d.LongestMatchPrefixOf("talkings"); s != "talking" || !ok {
+		t.Errorf("DAWG.LongestMatchPrefixOf(%q) = %q, %v, want %q, true", "talkings", s, ok, "talking")
+	}
+
+	b = runetrie.NewBuilder[string]()
+	err := b.InsertFrom(bufio.NewScanner(strings.NewReader("walk\ntalk\n")))
+	if !errors.Is(err, runetrie.ErrUnsortedEntry) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBuilder_TrieAll(t *testing.T) {
+	tr := runetrie.Must(runetrie.NewCaseInsensitiveTrie("a", "_a", "[", "ſ", "ΣΑΣ"))
+	b := runetrie.NewCaseInsensitiveBuilder[string]()
+	for s := range tr.All() {
+		if err := b.Insert(s); err != nil {
+			t.Fatalf("Builder.Insert(%q): unexpected error: %v", s, err)
+		}
+	}
+
+	d := b.Finish()
+	index := 0
+	for s := range tr.All() {
+		if got, ok := d.Index(s); got != index || !ok {
+			t.Errorf("DAWG.Index(%q) = %d, %v, want %d, true", s, got, ok, index)
+		}
+		index++
+	}
+}
+
+func TestBuilder_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	alphabet := []rune("ab√あ")
+	for i := 0; i < 200; i++ {
+		set := make([]string, rnd.Intn(32))
+		for j := range set {
+			set[j] = randomString(rnd, alphabet, 6)
+		}
+		slices.Sort(set)
+		targets := make([]string, 32)
+		for j := range targets {
+			targets[j] = randomString(rnd, alphabet, 8)
+		}
+
+		b := runetrie.NewBuilder[string]()
+		for _, s := range set {
+			if err := b.Insert(s); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		d, want := b.Finish(), runetrie.NewTrie(set...).Minimize()
+		for _, target := range targets {
+			if got, want := d.MatchAny(target), want.MatchAny(target); got != want {
+				t.Errorf("DAWG.MatchAny(%q) = %v, want %v", target, got, want)
+			}
+			if got, want := d.MatchAnyPrefixOf(target), want.MatchAnyPrefixOf(target); got != want {
+				t.Errorf("DAWG.MatchAnyPrefixOf(%q) = %v, want %v", target, got, want)
+			}
+
+			got, gotOK := d.LongestMatchPrefixOf(target)
+			wantS, wantOK := want.LongestMatchPrefixOf(target)
+			if got != wantS || gotOK != wantOK {
+				t.Errorf("DAWG.LongestMatchPrefixOf(%q) = %q, %v, want %q, %v", target, got, gotOK, wantS, wantOK)
+			}
+
+			gotIndex, gotOK := d.Index(target)
+			wantIndex, wantOK := want.Index(target)
+			if gotIndex != wantIndex || gotOK != wantOK {
+				t.Errorf("DAWG.Index(%q) = %d, %v, want %d, %v", target, gotIndex, gotOK, wantIndex, wantOK)
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package runetrie
+
+// Cursor is a position in a Trie for rune-by-rune traversal.
+// It is useful to integrate the Trie into hand-written lexers, which feed the input one rune at a time.
+// A Cursor must not be used after the Trie is modified.
+type Cursor[T ~string] struct {
+	root  *Trie[T]
+	node  *Trie[T]
+	off   int // the byte offset in the label of the node
+	depth int
+}
+
+// Cursor returns a new Cursor at the root of the Trie.
+// It panics if the Trie is normalized by WithNormalization, WithRuneMapper, WithWidthInsensitive or WithKanaInsensitive.
+func (t *Trie[T]) Cursor() *Cursor[T] {
+	t.mustNotBeNormalized("Cursor")
+	return &Cursor[T]{root: t, node: t}
+}
+
+// Step advances the Cursor by the given rune.
+// In case insensitive mode, the rune is matched in a case insensitive manner.
+// It returns false and leaves the Cursor unchanged if no string in the Trie continues with the rune.
+func (c *Cursor[T]) Step(r rune) bool {
+	leaf, off := c.node.next(r, c.off, c.root.i)
+	if leaf == nil {
+		return false
+	}
+	c.node, c.off = leaf, off
+	c.depth++
+	return true
+}
+
+// IsTerminal checks if the runes stepped so far match any of the strings in the Trie.
+// It returns the matched string and true if there is a match, or an empty string and false otherwise.
+func (c *Cursor[T]) IsTerminal() (T, bool) {
+	if c.off == len(c.node.p) && c.node.e {
+		return c.node.s, true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// CanContinue checks if any of the strings in the Trie are longer than the runes stepped so far.
+// It returns false if no further Step can succeed.
+func (c *Cursor[T]) CanContinue() bool {
+	return c.off < len(c.node.p) || len(c.node.m) != 0
+}
+
+// Depth returns the number of runes stepped so far.
+func (c *Cursor[T]) Depth() int {
+	return c.depth
+}
+
+// Reset moves the Cursor back to the root of the Trie.
+func (c *Cursor[T]) Reset() {
+	c.node = c.root
+	c.off = 0
+	c.depth = 0
+}
@@ -0,0 +1,98 @@
+package runetrie_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/runetrie"
+)
+
+func Test_Cursor(t *testing.T) {
+	type state struct {
+		Stepped     bool
+		Terminal    string
+		IsTerminal  bool
+		CanContinue bool
+		Depth       int
+	}
+	tests := []struct {
+		name  string
+		set   []string
+		input string
+		want  []state
+	}{
+		{
+			name:  "Empty",
+			set:   []string{},
+			input: "a",
+			want:  []state{{false, "", false, false, 0}},
+		},
+		{
+			name:  "Prefixes",
+			set:   []string{"<", "<=", "<<="},
+			input: "<<=",
+			want: []state{
+				{true, "<", true, true, 1},
+				{true, "", false, true, 2},
+				{true, "<<=", true, false, 3},
+			},
+		},
+		{
+			name:  "Mismatch",
+			set:   []string{"<", "<="},
+			input: "<>",
+			want: []state{
+				{true, "<", true, true, 1},
+				{false, "<", true, true, 1},
+			},
+		},
+		{
+			name:  "MultiByte",
+			set:   []string{"東京都"},
+			input: "東京都",
+			want: []state{
+				{true, "", false, true, 1},
+				{true, "", false, true, 2},
+				{true, "東京都", true, false, 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := runetrie.NewTrie(tt.set...).Cursor()
+			var got []state
+			for _, r := range tt.input {
+				stepped := c.Step(r)
+				terminal, ok := c.IsTerminal()
+				got = append(got, state{stepped, terminal, ok, c.CanContinue(), c.Depth()})
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Cursor mismatch.\n%s", diff)
+			}
+
+			c.Reset()
+			if c.Depth() != 0 {
+				t.Errorf("Cursor.Depth() = %d after Reset, want 0", c.Depth())
+			}
+		})
+	}
+}
+
+func Test_CaseInsensitiveCursor(t *testing.T) {
+	c := runetrie.Must(runetrie.NewCaseInsensitiveTrie("Select", "SET")).Cursor()
+	for _, r := range "sEl" {
+		if !c.Step(r) {
+			t.Fatalf("Cursor.Step(%q) = false", r)
+		}
+	}
+	c.Reset()
+	for _, r := range "set" {
+		if !c.Step(r) {
+			t.Fatalf("Cursor.Step(%q) = false", r)
+		}
+	}
+	if got, ok := c.IsTerminal(); !ok || got != "SET" {
+		t.Errorf("Cursor.IsTerminal() = (%v, %v), want (SET, true)", got, ok)
+	}
+}
@@ -0,0 +1,359 @@
+package runetrie
+
+import (
+	"encoding/binary"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// DAWG is an immutable directed acyclic word graph, which is a minimal automaton of the strings.
+// It is built by Trie.Minimize and answers the same queries as the Trie,
+// but the equivalent subtrees such as the shared suffixes are merged into one,
+// so it is much smaller with natural language word lists.
+// It also maps each string to a dense index by Index.
+// It is safe for concurrent use.
+type DAWG[T ~string] struct {
+	i bool // case insensitive
+	l struct {
+		max, min int
+	}
+	nodes []dawgNode // the root is the first node, and the last one is a sentinel
+	edges []dawgEdge
+
+	// the strings ordered by their indices, only in case insensitive mode.
+	// in case sensitive mode, the matched strings are the substrings of the given strings.
+	keys string
+	offs []uint32
+}
+
+type dawgNode struct {
+	edges uint32 // the offset of the edges, which end at the offset of the next node
+	count uint32 // the number of the strings in the subgraph
+	e     bool   // terminal
+}
+
+type dawgEdge struct {
+	c  rune
+	to int32
+}
+
+// Minimize builds a DAWG from the strings in the Trie.
+// The DAWG is a snapshot of the Trie at the time of Minimize, so it is not affected by later changes to the Trie.
+// It panics if the Trie is normalized by WithNormalization, WithRuneMapper, WithWidthInsensitive or WithKanaInsensitive.
+func (t *Trie[T]) Minimize() *DAWG[T] {
+	t.mustNotBeNormalized("Minimize")
+	type entry struct {
+		folded string
+		s      T
+	}
+
+	var entries []entry
+	for s := range t.All() {
+		entries = append(entries, entry{folded: foldString(string(s), t.i), s: s})
+	}
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		return strings.Compare(a.folded, b.folded)
+	})
+	entries = slices.CompactFunc(entries, func(a, b entry) bool {
+		return a.folded == b.folded
+	})
+
+	b := newDAWGBuilder(t.i)
+	for _, e := range entries {
+		b.insert(e.folded, string(e.s))
+	}
+	d := buildDAWG[T](b)
+	d.l = t.l
+	return d
+}
+
+// Len returns the number of the strings in the DAWG.
+func (d *DAWG[T]) Len() int {
+	return int(d.nodes[0].count)
+}
+
+// MatchAny checks if any of the strings in the DAWG match the given string.
+// It returns true if there is a match, false otherwise.
+func (d *DAWG[T]) MatchAny(s T) bool {
+	_, ok := d.Index(s)
+	return ok
+}
+
+// Index returns the index of the string in the DAWG which matches the given string.
+// The indices are dense from 0 to Len()-1 in the lexicographic order of the (case folded) strings.
+// It returns the index and true if there is a match, or -1 and false otherwise.
+func (d *DAWG[T]) Index(s T) (int, bool) {
+	if len(s) < d.l.min || d.l.max < len(s) {
+		return -1, false
+	}
+
+	node, index := int32(0), 0
+	for i := 0; i < len(s); {
+		c, size := rune(s[i]), 1
+		if c >= utf8.RuneSelf {
+			c, size = utf8.DecodeRuneInString(string(s[i:]))
+		}
+		i += size
+
+		if node = d.step(node, c, &index); node < 0 {
+			return -1, false
+		}
+	}
+	if !d.nodes[node].e {
+		return -1, false
+	}
+	return index, true
+}
+
+// MatchAnyPrefixOf checks if any of the strings in the DAWG match any prefix of the given string.
+// It returns true if there is a match, false otherwise.
+func (d *DAWG[T]) MatchAnyPrefixOf(s T) bool {
+	_, end := d.shortestPrefix(s)
+	return end >= 0
+}
+
+// MatchPrefixOf checks if the given string's prefix matches any of the strings in the DAWG.
+// It returns the shortest matched string and true if there is a match, or an empty string and false otherwise.
+func (d *DAWG[T]) MatchPrefixOf(s T) (T, bool) {
+	if index, end := d.shortestPrefix(s); end >= 0 {
+		return d.key(s, index, end), true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// LongestMatchPrefixOf checks if the given string's prefix matches any of the strings in the DAWG.
+// It returns the longest matched string and true if there is a match, or an empty string and false otherwise.
+func (d *DAWG[T]) LongestMatchPrefixOf(s T) (T, bool) {
+	if len(s) < d.l.min {
+		var zero T
+		return zero, false
+	}
+	if len(s) > d.l.max {
+		s = s[:d.l.max]
+	}
+
+	result, end := -1, -1
+	node, index := int32(0), 0
+	for i := 0; ; {
+		if d.nodes[node].e {
+			result, end = index, i
+		}
+		if i == len(s) {
+			break
+		}
+
+		c, size := rune(s[i]), 1
+		if c >= utf8.RuneSelf {
+			c, size = utf8.DecodeRuneInString(string(s[i:]))
+		}
+		i += size
+
+		if node = d.step(node, c, &index); node < 0 {
+			break
+		}
+	}
+	if end >= 0 {
+		return d.key(s, result, end), true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// shortestPrefix returns the index of the shortest string in the DAWG which is a prefix of the given string
+// and the end of the prefix, or -1 as the end if there is no match.
+func (d *DAWG[T]) shortestPrefix(s T) (int, int) {
+	if len(s) < d.l.min {
+		return -1, -1
+	}
+	if len(s) > d.l.max {
+		s = s[:d.l.max]
+	}
+
+	node, index := int32(0), 0
+	for i := 0; ; {
+		if d.nodes[node].e {
+			return index, i
+		}
+		if i == len(s) {
+			return -1, -1
+		}
+
+		c, size := rune(s[i]), 1
+		if c >= utf8.RuneSelf {
+			c, size = utf8.DecodeRuneInString(string(s[i:]))
+		}
+		i += size
+
+		if node = d.step(node, c, &index); node < 0 {
+			return -1, -1
+		}
+	}
+}
+
+// step returns the node reached from the given node by the given rune, or -1 if there is none.
+// It adds the number of the strings which are ordered before the reached node to the index.
+func (d *DAWG[T]) step(node int32, c rune, index *int) int32 {
+	if d.i {
+		c = foldRune(c)
+	}
+
+	if d.nodes[node].e {
+		*index++
+	}
+	edges := d.edges[d.nodes[node].edges:d.nodes[node+1].edges]
+	for _, edge := range edges {
+		if edge.c == c {
+			return edge.to
+		}
+		if edge.c > c {
+			break
+		}
+		*index += int(d.nodes[edge.to].count)
+	}
+	return -1
+}
+
+func (d *DAWG[T]) key(s T, index, end int) T {
+	if d.i {
+		return T(d.keys[d.offs[index]:d.offs[index+1]])
+	}
+	return s[:end]
+}
+
+// dawgBuilder builds a DAWG from the sorted strings incrementally,
+// by the algorithm of Daciuk et al. which merges the equivalent states as soon as they are no longer changed.
+type dawgBuilder struct {
+	i      bool
+	states []dawgState
+	free   []int32          // the states which are merged into the others and can be reused
+	index  map[string]int32 // the merged states by their signatures
+	path   []int32          // the states on the path of the last string, which are not merged yet
+	last   []rune           // the runes of the last string
+
+	keys strings.Builder
+	offs []uint32
+}
+
+type dawgState struct {
+	edges []dawgEdge
+	e     bool
+}
+
+func newDAWGBuilder(caseInsensitive bool) *dawgBuilder {
+	return &dawgBuilder{
+		i:      caseInsensitive,
+		states: []dawgState{{}},
+		index:  map[string]int32{},
+		path:   []int32{0},
+		offs:   []uint32{0},
+	}
+}
+
+// insert adds the folded string, which must be greater than the last one, with the original string.
+func (b *dawgBuilder) insert(folded, s string) {
+	runes := []rune(folded)
+	common := 0
+	for common < len(runes) && common < len(b.last) && runes[common] == b.last[common] {
+		common++
+	}
+	b.merge(common)
+
+	for _, c := range runes[common:] {
+		from := b.path[len(b.path)-1]
+		to := b.newState()
+		b.states[from].edges = append(b.states[from].edges, dawgEdge{c: c, to: to})
+		b.path = append(b.path, to)
+	}
+	b.states[b.path[len(b.path)-1]].e = true
+	b.last = runes
+
+	if b.i {
+		b.keys.WriteString(s)
+		b.offs = append(b.offs, uint32(b.keys.Len()))
+	}
+}
+
+// merge replaces the states on the path deeper than the given depth with the equivalent states if any.
+func (b *dawgBuilder) merge(depth int) {
+	for k := len(b.path) - 1; k > depth; k-- {
+		state, parent := b.path[k], b.path[k-1]
+		sig := b.signature(state)
+		if merged, ok := b.index[sig]; ok {
+			edges := b.states[parent].edges
+			edges[len(edges)-1].to = merged
+			b.states[state] = dawgState{edges: b.states[state].edges[:0]}
+			b.free = append(b.free, state)
+		} else {
+			b.index[sig] = state
+		}
+	}
+	b.path = b.path[:depth+1]
+}
+
+func (b *dawgBuilder) newState() int32 {
+	if n := len(b.free); n != 0 {
+		state := b.free[n-1]
+		b.free = b.free[:n-1]
+		return state
+	}
+	b.states = append(b.states, dawgState{})
+	return int32(len(b.states) - 1)
+}
+
+// signature returns the key which is equal for the equivalent states whose children are already merged.
+func (b *dawgBuilder) signature(state int32) string {
+	buf := make([]byte, 0, 1+len(b.states[state].edges)*2*binary.MaxVarintLen32)
+	if b.states[state].e {
+		buf = append(buf, 1)
+	} else {
+		buf = append(buf, 0)
+	}
+	for _, edge := range b.states[state].edges {
+		buf = binary.AppendUvarint(buf, uint64(edge.c))
+		buf = binary.AppendUvarint(buf, uint64(edge.to))
+	}
+	return string(buf)
+}
+
+// buildDAWG merges the remaining states in the builder and returns the DAWG numbering the states in depth first order.
+func buildDAWG[T ~string](b *dawgBuilder) *DAWG[T] {
+	b.merge(0)
+
+	d := &DAWG[T]{i: b.i}
+	ids := map[int32]int32{}
+	var visit func(state int32) int32
+	visit = func(state int32) int32 {
+		if id, ok := ids[state]; ok {
+			return id
+		}
+		id := int32(len(d.nodes))
+		ids[state] = id
+		d.nodes = append(d.nodes, dawgNode{e: b.states[state].e})
+
+		edges := b.states[state].edges
+		start := len(d.edges)
+		d.edges = append(d.edges, edges...)
+		count := uint32(0)
+		if b.states[state].e {
+			count++
+		}
+		for i, edge := range edges {
+			to := visit(edge.to)
+			d.edges[start+i].to = to
+			count += d.nodes[to].count
+		}
+		d.nodes[id].edges, d.nodes[id].count = uint32(start), count
+		return id
+	}
+	visit(0)
+	d.nodes = append(d.nodes, dawgNode{edges: uint32(len(d.edges))})
+
+	if b.i {
+		d.keys, d.offs = b.keys.String(), b.offs
+	}
+	return d
+}
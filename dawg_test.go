@@ -0,0 +1,123 @@
+package runetrie_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/runetrie"
+)
+
+func Test_DAWG(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     []string
+		targets []string
+	}{
+		{
+			name:    "Empty",
+			set:     []string{},
+			targets: []string{"", "foo"},
+		},
+		{
+			name:    "EmptyEntry",
+			set:     []string{"", "A"},
+			targets: []string{"", "A", "AB", "B"},
+		},
+		{
+			name:    "Prefixes",
+			set:     []string{"A", "AA", "AAA", "ABCA"},
+			targets: []string{"", "A", "AA", "AAC", "AAAA", "ABC", "ABCABC", "abca"},
+		},
+		{
+			name:    "Suffixes",
+			set:     []string{"walk", "walking", "walked", "talk", "talking", "talked", "station", "nation"},
+			targets: []string{"walking", "talked", "talke", "tal", "stationary", "nations", "national", "Nation"},
+		},
+		{
+			name:    "MultiByte",
+			set:     []string{"東", "東京", "東京都", "京都", "éa", "aé"},
+			targets: []string{"東京都庁", "京", "京都", "éa", "aé", "ÉA"},
+		},
+		{
+			name:    "CaseFolding",
+			set:     []string{"Foo", "BAR", "ÀB", "ΣΑΣ"},
+			targets: []string{"foo", "FOOBAR", "bar", "àb", "σασ", "ΣΑς"},
+		},
+		{
+			name:    "FoldedOrder",
+			set:     []string{"a", "_a", "[", "ſ"},
+			targets: []string{"A", "_A", "[", "S", "s"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assertDAWG(t, runetrie.NewTrie(tt.set...), tt.targets)
+			assertDAWG(t, caseInsensitiveTrieOf(tt.set...), tt.targets)
+		})
+	}
+}
+
+func TestDAWG_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	alphabet := []rune("abAB√あ")
+	for i := 0; i < 200; i++ {
+		set := make([]string, rnd.Intn(32))
+		for j := range set {
+			set[j] = randomString(rnd, alphabet, 6)
+		}
+		targets := make([]string, 32)
+		for j := range targets {
+			targets[j] = randomString(rnd, alphabet, 8)
+		}
+
+		assertDAWG(t, runetrie.NewTrie(set...), targets)
+		assertDAWG(t, caseInsensitiveTrieOf(set...), targets)
+	}
+}
+
+func TestDAWG_Index(t *testing.T) {
+	tr := runetrie.Must(runetrie.NewCaseInsensitiveTrie("walking", "Talking", "talk", "walk", "", "ΣΑΣ"))
+	d := tr.Minimize()
+
+	var got []int
+	for s := range tr.All() {
+		index, ok := d.Index(s)
+		if !ok {
+			t.Errorf("DAWG.Index(%q) must match", s)
+		}
+		got = append(got, index)
+	}
+	if diff := cmp.Diff([]int{0, 1, 2, 3, 4, 5}, got); diff != "" {
+		t.Errorf("DAWG.Index mismatch.\n%s", diff)
+	}
+	if d.Len() != 6 {
+		t.Errorf("DAWG.Len() = %d, want 6", d.Len())
+	}
+	if index, ok := d.Index("TALKING"); index != 2 || !ok {
+		t.Errorf("DAWG.Index(%q) = %d, %v, want 2, true", "TALKING", index, ok)
+	}
+	if index, ok := d.Index("walke"); index != -1 || ok {
+		t.Errorf("DAWG.Index(%q) = %d, %v, want -1, false", "walke", index, ok)
+	}
+}
+
+func assertDAWG(t *testing.T, tr *runetrie.Trie[string], targets []string) {
+	t.Helper()
+	d := tr.Minimize()
+
+	assertLookup(t, "DAWG", d, tr, targets)
+
+	index := 0
+	for s := range tr.All() {
+		if got, ok := d.Index(s); got != index || !ok {
+			t.Errorf("DAWG.Index(%q) = %d, %v, want %d, true", s, got, ok, index)
+		}
+		index++
+	}
+	if d.Len() != index {
+		t.Errorf("DAWG.Len() = %d, want %d", d.Len(), index)
+	}
+}
@@ -0,0 +1,429 @@
+package runetrie
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"strings"
+	"unsafe"
+)
+
+// ErrInvalidFormat is returned when decoding data which is not a valid serialized Trie,
+// including the data which is truncated or fails the checksum.
+var ErrInvalidFormat = errors.New("invalid format")
+
+// ErrUnsupportedVersion is returned when decoding a serialized Trie written in an unknown format version.
+var ErrUnsupportedVersion = errors.New("unsupported version")
+
+// ErrNormalized is returned when encoding a Trie normalized by WithNormalization, WithRuneMapper,
+// WithWidthInsensitive or WithKanaInsensitive, whose normalization cannot be serialized.
+var ErrNormalized = errors.New("normalized trie")
+
+// The serialized Trie consists of:
+//
+//	magic    [4]byte  "RTRI"
+//	version  byte
+//	flags    byte     trieFlag*
+//	count    uvarint  the number of the strings
+//	min, max uvarint  the length bounds of the strings
+//	strings  count × (uvarint length, bytes) in the order of All
+//	checksum uint32   little endian CRC-32 (IEEE) of all the preceding bytes
+const (
+	trieMagic   = "RTRI"
+	trieVersion = 1
+
+	trieFlagCaseInsensitive = 1 << 0
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// The encoded data preserves the case sensitivity and the original strings of the Trie.
+func (t *Trie[T]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := t.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// It replaces the contents of the Trie with the decoded ones as ReadFrom.
+// It returns ErrInvalidFormat if the data is broken, and leaves the Trie unchanged in that case.
+func (t *Trie[T]) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	decoded := Trie[T]{r: t.r, n: t.n}
+	if _, err := decoded.ReadFrom(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidFormat, r.Len())
+	}
+
+	*t = decoded
+	return nil
+}
+
+// WriteTo implements io.WriterTo.
+// It writes the Trie in the same format as MarshalBinary.
+// It returns ErrNormalized without writing anything if the Trie is normalized.
+func (t *Trie[T]) WriteTo(w io.Writer) (int64, error) {
+	if t.n != nil {
+		return 0, ErrNormalized
+	}
+
+	count := 0
+	for range t.All() {
+		count++
+	}
+
+	var flags byte
+	if t.i {
+		flags |= trieFlagCaseInsensitive
+	}
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	h := crc32.NewIEEE()
+	mw := io.MultiWriter(bw, h)
+
+	buf := append([]byte(trieMagic), trieVersion, flags)
+	buf = binary.AppendUvarint(buf, uint64(count))
+	buf = binary.AppendUvarint(buf, uint64(t.l.min))
+	buf = binary.AppendUvarint(buf, uint64(t.l.max))
+	if _, err := mw.Write(buf); err != nil {
+		return cw.n, err
+	}
+	for s := range t.All() {
+		buf = binary.AppendUvarint(buf[:0], uint64(len(s)))
+		buf = append(buf, s...)
+		if _, err := mw.Write(buf); err != nil {
+			return cw.n, err
+		}
+	}
+	if _, err := bw.Write(binary.LittleEndian.AppendUint32(buf[:0], h.Sum32())); err != nil {
+		return cw.n, err
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ReadFrom implements io.ReaderFrom.
+// It replaces the contents of the Trie with the ones read in the format of MarshalBinary.
+// The conflict resolver and the normalization of the Trie, if any, are kept and applied to the strings read.
+// It returns ErrInvalidFormat if the data is broken, and leaves the Trie unchanged in that case.
+// If r does not implement io.ByteReader, it is wrapped by bufio.Reader, which may read beyond the end of the Trie.
+func (t *Trie[T]) ReadFrom(r io.Reader) (int64, error) {
+	br, ok := r.(interface {
+		io.Reader
+		io.ByteReader
+	})
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	cr := &checksumReader{r: br, h: crc32.NewIEEE()}
+
+	var header [len(trieMagic) + 2]byte
+	if _, err := io.ReadFull(cr, header[:]); err != nil {
+		return cr.n, formatError(err)
+	}
+	if string(header[:len(trieMagic)]) != trieMagic {
+		return cr.n, fmt.Errorf("%w: bad magic %q", ErrInvalidFormat, header[:len(trieMagic)])
+	}
+	if version := header[len(trieMagic)]; version != trieVersion {
+		return cr.n, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+	flags := header[len(trieMagic)+1]
+
+	var fields [3]uint64 // count, min, max
+	for i := range fields {
+		v, err := binary.ReadUvarint(cr)
+		if err != nil {
+			return cr.n, formatError(err)
+		}
+		fields[i] = v
+	}
+
+	decoded := &Trie[T]{i: flags&trieFlagCaseInsensitive != 0, r: t.r, n: t.n}
+	var sb strings.Builder
+	for i := uint64(0); i < fields[0]; i++ {
+		n, err := binary.ReadUvarint(cr)
+		if err != nil {
+			return cr.n, formatError(err)
+		}
+
+		// grows the buffer as reading not to trust the broken length
+		sb.Reset()
+		if _, err := io.CopyN(&sb, cr, int64(n)); err != nil {
+			return cr.n, formatError(err)
+		}
+		if err := decoded.Add(T(sb.String())); err != nil {
+			return cr.n, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+		}
+	}
+
+	sum := cr.h.Sum32()
+	var checksum [4]byte
+	if _, err := io.ReadFull(cr, checksum[:]); err != nil {
+		return cr.n, formatError(err)
+	}
+	if binary.LittleEndian.Uint32(checksum[:]) != sum {
+		return cr.n, fmt.Errorf("%w: checksum mismatch", ErrInvalidFormat)
+	}
+	if uint64(decoded.l.min) != fields[1] || uint64(decoded.l.max) != fields[2] {
+		return cr.n, fmt.Errorf("%w: length bounds mismatch", ErrInvalidFormat)
+	}
+
+	*t = *decoded
+	return cr.n, nil
+}
+
+func formatError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %w", ErrInvalidFormat, io.ErrUnexpectedEOF)
+	}
+	return err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+type checksumReader struct {
+	r interface {
+		io.Reader
+		io.ByteReader
+	}
+	h hash.Hash32
+	n int64
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.h.Write(p[:n])
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *checksumReader) ReadByte() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err == nil {
+		r.h.Write([]byte{b})
+		r.n++
+	}
+	return b, err
+}
+
+// The serialized FrozenTrie is laid out to be used in place by LoadMapped:
+//
+//	magic    [4]byte  "RTRF"
+//	version  byte
+//	flags    byte     trieFlag*
+//	padding  [2]byte
+//	min, max uint64   the length bounds of the strings
+//	cells    uint64   the number of the cells of the double-array
+//	count    uint64   the number of the strings
+//	size     uint64   the total bytes of the strings
+//	base     cells × int32
+//	check    cells × int32
+//	offs     (count+1) × uint32
+//	strings  size bytes
+//	checksum uint32   CRC-32 (IEEE) of all the preceding bytes
+//
+// All the integers are little endian.
+const (
+	frozenMagic      = "RTRF"
+	frozenVersion    = 1
+	frozenHeaderSize = 48
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// The encoded data can be loaded by UnmarshalBinary or LoadMapped.
+func (ft *FrozenTrie[T]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := ft.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// It replaces the contents of the FrozenTrie with the decoded ones, which are copied from the data.
+// It returns ErrInvalidFormat if the data is broken, and leaves the FrozenTrie unchanged in that case.
+func (ft *FrozenTrie[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < frozenHeaderSize+4 {
+		return fmt.Errorf("%w: %w", ErrInvalidFormat, io.ErrUnexpectedEOF)
+	}
+	body, checksum := data[:len(data)-4], binary.LittleEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(body) != checksum {
+		return fmt.Errorf("%w: checksum mismatch", ErrInvalidFormat)
+	}
+
+	decoded, err := decodeFrozenTrie[T](data, false)
+	if err != nil {
+		return err
+	}
+	*ft = *decoded
+	return nil
+}
+
+// WriteTo implements io.WriterTo.
+// It writes the FrozenTrie in the same format as MarshalBinary.
+func (ft *FrozenTrie[T]) WriteTo(w io.Writer) (int64, error) {
+	var flags byte
+	if ft.i {
+		flags |= trieFlagCaseInsensitive
+	}
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	h := crc32.NewIEEE()
+	mw := io.MultiWriter(bw, h)
+
+	buf := make([]byte, frozenHeaderSize, 4096)
+	copy(buf, frozenMagic)
+	buf[len(frozenMagic)] = frozenVersion
+	buf[len(frozenMagic)+1] = flags
+	binary.LittleEndian.PutUint64(buf[8:], uint64(ft.l.min))
+	binary.LittleEndian.PutUint64(buf[16:], uint64(ft.l.max))
+	binary.LittleEndian.PutUint64(buf[24:], uint64(len(ft.base)))
+	binary.LittleEndian.PutUint64(buf[32:], uint64(len(ft.offs)-1))
+	binary.LittleEndian.PutUint64(buf[40:], uint64(len(ft.keys)))
+	if _, err := mw.Write(buf); err != nil {
+		return cw.n, err
+	}
+
+	for _, array := range [][]int32{ft.base, ft.check} {
+		for _, v := range array {
+			buf = binary.LittleEndian.AppendUint32(buf[:0], uint32(v))
+			if _, err := mw.Write(buf); err != nil {
+				return cw.n, err
+			}
+		}
+	}
+	for _, v := range ft.offs {
+		buf = binary.LittleEndian.AppendUint32(buf[:0], v)
+		if _, err := mw.Write(buf); err != nil {
+			return cw.n, err
+		}
+	}
+	if _, err := io.WriteString(mw, ft.keys); err != nil {
+		return cw.n, err
+	}
+
+	if _, err := bw.Write(binary.LittleEndian.AppendUint32(buf[:0], h.Sum32())); err != nil {
+		return cw.n, err
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// decodeFrozenTrie decodes the serialized FrozenTrie with a structural check, except for the checksum.
+// If inPlace is true, the FrozenTrie refers to the data instead of copying it where possible.
+func decodeFrozenTrie[T ~string](data []byte, inPlace bool) (*FrozenTrie[T], error) {
+	if len(data) < frozenHeaderSize+4 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, io.ErrUnexpectedEOF)
+	}
+	if string(data[:len(frozenMagic)]) != frozenMagic {
+		return nil, fmt.Errorf("%w: bad magic %q", ErrInvalidFormat, data[:len(frozenMagic)])
+	}
+	if version := data[len(frozenMagic)]; version != frozenVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+	flags := data[len(frozenMagic)+1]
+
+	var fields [5]uint64 // min, max, cells, count, size
+	for i := range fields {
+		fields[i] = binary.LittleEndian.Uint64(data[8+8*i:])
+	}
+	cells, count, size := fields[2], fields[3], fields[4]
+	if cells > math.MaxInt32 || count >= math.MaxInt32 || size > math.MaxUint32 || fields[0] > fields[1] || fields[1] > math.MaxInt32 {
+		return nil, fmt.Errorf("%w: too large", ErrInvalidFormat)
+	}
+	if uint64(len(data)) != frozenHeaderSize+8*cells+4*(count+1)+size+4 {
+		return nil, fmt.Errorf("%w: size mismatch", ErrInvalidFormat)
+	}
+
+	ft := &FrozenTrie[T]{i: flags&trieFlagCaseInsensitive != 0, mapped: inPlace}
+	ft.l.min, ft.l.max = int(fields[0]), int(fields[1])
+
+	data = data[frozenHeaderSize:]
+	base, data := data[:4*cells], data[4*cells:]
+	check, data := data[:4*cells], data[4*cells:]
+	offs, data := data[:4*(count+1)], data[4*(count+1):]
+	keys := data[:size]
+	if inPlace && nativeLittleEndian {
+		ft.base = unsafe.Slice((*int32)(unsafe.Pointer(unsafe.SliceData(base))), cells)
+		ft.check = unsafe.Slice((*int32)(unsafe.Pointer(unsafe.SliceData(check))), cells)
+		ft.offs = unsafe.Slice((*uint32)(unsafe.Pointer(unsafe.SliceData(offs))), count+1)
+	} else {
+		ft.base = make([]int32, cells)
+		ft.check = make([]int32, cells)
+		ft.offs = make([]uint32, count+1)
+		for i := range ft.base {
+			ft.base[i] = int32(binary.LittleEndian.Uint32(base[4*i:]))
+			ft.check[i] = int32(binary.LittleEndian.Uint32(check[4*i:]))
+		}
+		for i := range ft.offs {
+			ft.offs[i] = binary.LittleEndian.Uint32(offs[4*i:])
+		}
+	}
+	if inPlace {
+		ft.keys = unsafe.String(unsafe.SliceData(keys), len(keys))
+	} else {
+		ft.keys = string(keys)
+	}
+
+	if err := ft.validate(); err != nil {
+		return nil, err
+	}
+	return ft, nil
+}
+
+// validate checks that the lookups never go out of the bounds of the arrays.
+func (ft *FrozenTrie[T]) validate() error {
+	if len(ft.base) == 0 {
+		return fmt.Errorf("%w: no root", ErrInvalidFormat)
+	}
+	if ft.offs[0] != 0 || ft.offs[len(ft.offs)-1] != uint32(len(ft.keys)) {
+		return fmt.Errorf("%w: broken offsets", ErrInvalidFormat)
+	}
+	for i := 1; i < len(ft.offs); i++ {
+		if ft.offs[i-1] > ft.offs[i] {
+			return fmt.Errorf("%w: broken offsets", ErrInvalidFormat)
+		}
+	}
+
+	count := int64(len(ft.offs) - 1)
+	for i, parent := range ft.check {
+		if parent < 0 {
+			continue
+		}
+		if int(parent) >= len(ft.check) {
+			return fmt.Errorf("%w: broken cell %d", ErrInvalidFormat, i)
+		}
+		code := int64(i) - int64(ft.base[parent])
+		if code < frozenTerminalCode || code > math.MaxUint8+1 {
+			return fmt.Errorf("%w: broken cell %d", ErrInvalidFormat, i)
+		}
+		if code == frozenTerminalCode {
+			if k := -int64(ft.base[i]) - 1; k < 0 || k >= count {
+				return fmt.Errorf("%w: broken cell %d", ErrInvalidFormat, i)
+			}
+		}
+	}
+	return nil
+}
+
+var nativeLittleEndian = binary.NativeEndian.Uint16([]byte{1, 0}) == 1
@@ -0,0 +1,200 @@
+package runetrie_test
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/runetrie"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*runetrie.Trie[string])(nil)
+	_ encoding.BinaryUnmarshaler = (*runetrie.Trie[string])(nil)
+)
+
+func TestMarshalBinary(t *testing.T) {
+	tests := []struct {
+		name string
+		trie *runetrie.Trie[string]
+	}{
+		{
+			name: "Empty",
+			trie: runetrie.NewTrie[string](),
+		},
+		{
+			name: "EmptyEntry",
+			trie: runetrie.NewTrie("", "A"),
+		},
+		{
+			name: "CaseSensitive",
+			trie: runetrie.NewTrie("foo", "Foo", "foobar", "東京都"),
+		},
+		{
+			name: "CaseInsensitive",
+			trie: runetrie.Must(runetrie.NewCaseInsensitiveTrie("Foo", "BAR", "application/JSON", "東京都")),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.trie.MarshalBinary()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got runetrie.Trie[string]
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.trie, &got, cmp.Exporter(func(reflect.Type) bool { return true })); diff != "" {
+				t.Errorf("Trie.UnmarshalBinary() mismatch.\n%s", diff)
+			}
+
+			var buf bytes.Buffer
+			n, err := tt.trie.WriteTo(&buf)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != int64(len(data)) || !bytes.Equal(buf.Bytes(), data) {
+				t.Errorf("Trie.WriteTo() = %d, want %d", n, len(data))
+			}
+		})
+	}
+}
+
+func TestMarshalBinary_Normalized(t *testing.T) {
+	tr := runetrie.NewTrieWithOptions[string](runetrie.WithWidthInsensitive())
+	if err := tr.Add("ガ"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := tr.MarshalBinary(); !errors.Is(err, runetrie.ErrNormalized) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if n, err := tr.WriteTo(&buf); !errors.Is(err, runetrie.ErrNormalized) || n != 0 || buf.Len() != 0 {
+		t.Errorf("Trie.WriteTo() = %d, %v", n, err)
+	}
+
+	data, err := runetrie.NewTrie("ギ").MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tr.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tr.MatchAny("ｶﾞ") || !tr.MatchAny("ｷﾞ") {
+		t.Error("Trie.UnmarshalBinary() must keep the normalization of the Trie")
+	}
+}
+
+func TestUnmarshalBinary_ConflictResolver(t *testing.T) {
+	data, err := runetrie.Must(runetrie.NewCaseInsensitiveTrie("foo")).MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tr := runetrie.NewTrieWithOptions[string](runetrie.WithCaseInsensitive(), runetrie.WithConflictPolicy(runetrie.ConflictReplace))
+	if err := tr.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tr.Add("FOO"); err != nil {
+		t.Errorf("Trie.UnmarshalBinary() must keep the conflict resolver of the Trie: %v", err)
+	}
+	if got, ok := tr.MatchPrefixOf("foo"); got != "FOO" || !ok {
+		t.Errorf("Trie.MatchPrefixOf() = %q, %v", got, ok)
+	}
+}
+
+func TestReadFrom_Stream(t *testing.T) {
+	var buf bytes.Buffer
+	first := runetrie.NewTrie("foo", "bar")
+	second := runetrie.Must(runetrie.NewCaseInsensitiveTrie("Baz"))
+	if _, err := first.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := second.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := bytes.NewReader(buf.Bytes())
+	for _, want := range []*runetrie.Trie[string]{first, second} {
+		var got runetrie.Trie[string]
+		if _, err := got.ReadFrom(r); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(want, &got, cmp.Exporter(func(reflect.Type) bool { return true })); diff != "" {
+			t.Errorf("Trie.ReadFrom() mismatch.\n%s", diff)
+		}
+	}
+	if r.Len() != 0 {
+		t.Errorf("%d bytes left", r.Len())
+	}
+}
+
+func TestUnmarshalBinary_Error(t *testing.T) {
+	data, err := runetrie.NewTrie("foo", "bar").MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data func() []byte
+		want error
+	}{
+		{
+			name: "Empty",
+			data: func() []byte { return nil },
+			want: runetrie.ErrInvalidFormat,
+		},
+		{
+			name: "BadMagic",
+			data: func() []byte { return append([]byte("XTRI"), data[4:]...) },
+			want: runetrie.ErrInvalidFormat,
+		},
+		{
+			name: "UnsupportedVersion",
+			data: func() []byte {
+				d := bytes.Clone(data)
+				d[4] = 255
+				return d
+			},
+			want: runetrie.ErrUnsupportedVersion,
+		},
+		{
+			name: "Truncated",
+			data: func() []byte { return data[:len(data)-5] },
+			want: runetrie.ErrInvalidFormat,
+		},
+		{
+			name: "Corrupted",
+			data: func() []byte {
+				d := bytes.Clone(data)
+				d[len(d)-6] ^= 1
+				return d
+			},
+			want: runetrie.ErrInvalidFormat,
+		},
+		{
+			name: "TrailingBytes",
+			data: func() []byte { return append(bytes.Clone(data), 0) },
+			want: runetrie.ErrInvalidFormat,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrie("hoge")
+			if err := tr.UnmarshalBinary(tt.data()); !errors.Is(err, tt.want) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tr.MatchAny("hoge") {
+				t.Error("Trie.UnmarshalBinary() must leave the Trie unchanged on error")
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package runetrie_test
 
 import (
+	"bufio"
 	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/karupanerura/runetrie"
+	"golang.org/x/text/unicode/norm"
 )
 
 func ExampleNewTrie() {
@@ -31,6 +35,17 @@ func ExampleTrie_Add() {
 	// true
 }
 
+func ExampleTrie_Remove() {
+	trie := runetrie.NewTrie("foo", "bar", "buz")
+	fmt.Println(trie.Remove("foo", "hoge"))
+	fmt.Println(trie.MatchAny("foo"))
+	fmt.Println(trie.MatchAny("bar"))
+	// Output:
+	// 1
+	// false
+	// true
+}
+
 func ExampleTrie_MatchAny() {
 	trie := runetrie.NewTrie("foo", "bar", "buz")
 	fmt.Println(trie.MatchAny("fo"))
@@ -104,3 +119,267 @@ func ExampleTrie_LongestMatchPrefixOf_longest() {
 	// Output:
 	// ABC
 }
+
+func ExampleNewTrieMap() {
+	tm := runetrie.NewTrieMap(map[string]string{
+		"/":        "root",
+		"/api/":    "api",
+		"/api/v1/": "api-v1",
+	})
+	fmt.Println(tm.LongestPrefixValue("/api/v1/users"))
+	fmt.Println(tm.ShortestPrefixValue("/api/v1/users"))
+	fmt.Println(tm.PrefixValues("/api/v1/users"))
+	// Output:
+	// api-v1 true
+	// root true
+	// [root api api-v1]
+}
+
+func ExampleTrie_LongestMatchPrefixOf_fallback() {
+	trie := runetrie.NewTrie("", "/api/", "/static/")
+	for _, path := range []string{"/api/users", "/static/app.js", "/index.html"} {
+		matched, _ := trie.LongestMatchPrefixOf(path)
+		fmt.Printf("%q\n", matched)
+	}
+
+	// Output:
+	// "/api/"
+	// "/static/"
+	// ""
+}
+
+func ExampleTrie_All() {
+	trie := runetrie.NewTrie("foo", "bar", "buz", "foobar")
+	for s := range trie.All() {
+		fmt.Println(s)
+	}
+	// Output:
+	// bar
+	// buz
+	// foo
+	// foobar
+}
+
+func ExampleTrie_Backward() {
+	trie := runetrie.NewTrie("foo", "bar", "buz", "foobar")
+	for s := range trie.Backward() {
+		fmt.Println(s)
+	}
+	// Output:
+	// foobar
+	// foo
+	// buz
+	// bar
+}
+
+func ExampleTrie_KeysWithPrefix() {
+	trie := runetrie.Must(runetrie.NewCaseInsensitiveTrie("Application/JSON", "Application/XML", "text/html"))
+	for s := range trie.KeysWithPrefix("app") {
+		fmt.Println(s)
+	}
+	// Output:
+	// Application/JSON
+	// Application/XML
+}
+
+func ExampleTrie_AllPrefixesOf() {
+	trie := runetrie.NewTrie("東", "東京", "東京都", "京都")
+	for end, matched := range trie.AllPrefixesOf("東京都庁") {
+		fmt.Println(end, matched)
+	}
+	// Output:
+	// 3 東
+	// 6 東京
+	// 9 東京都
+}
+
+func ExampleMatcher_Matches() {
+	matcher := runetrie.Must(runetrie.NewCaseInsensitiveTrie("he", "she", "his", "hers")).Compile()
+	for m := range matcher.Matches("USHERS") {
+		fmt.Println(m.Key, m.Start, m.End)
+	}
+	// Output:
+	// she 1 4
+	// he 2 4
+	// hers 2 6
+}
+
+func ExampleTrie_FindAllString() {
+	trie := runetrie.NewTrie("foo", "foobar", "baz")
+	fmt.Println(trie.FindAllString("foobarbaz foo", -1))
+	fmt.Println(trie.FindAllFirstString("foobarbaz foo", -1))
+	fmt.Println(trie.FindAllIndex("foobarbaz foo", 2))
+	// Output:
+	// [foobar baz foo]
+	// [foo baz foo]
+	// [[0 6] [6 9]]
+}
+
+func ExampleNewCaseInsensitiveReplacer() {
+	r, err := runetrie.NewCaseInsensitiveReplacer(map[string]string{
+		"colour": "color",
+		"grey":   "gray",
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(r.Replace("Colour: GREY"))
+	// Output:
+	// color: gray
+}
+
+func ExampleMatcher_NewScanner() {
+	matcher := runetrie.NewTrie("ERROR", "WARN").Compile()
+	s := matcher.NewScanner(strings.NewReader("INFO ok\nWARN slow\nERROR failed\n"))
+	for s.Scan() {
+		m := s.Match()
+		fmt.Println(m.Key, m.Start, m.End)
+	}
+	if err := s.Err(); err != nil {
+		panic(err)
+	}
+	// Output:
+	// WARN 8 12
+	// ERROR 18 23
+}
+
+func ExampleCursor() {
+	operators := runetrie.NewTrie("<", "<=", "<<", "<<=", "=", "==")
+
+	// a maximal munch tokenizer
+	input := []rune("a<<=b==c")
+	c := operators.Cursor()
+	for i := 0; i < len(input); {
+		c.Reset()
+		token, end := "", i+1
+		for j := i; j < len(input) && c.Step(input[j]); j++ {
+			if op, ok := c.IsTerminal(); ok {
+				token, end = op, j+1
+			}
+			if !c.CanContinue() {
+				break
+			}
+		}
+		if token == "" {
+			token = string(input[i])
+		}
+		fmt.Printf("%q ", token)
+		i = end
+	}
+	fmt.Println()
+	// Output:
+	// "a" "<<=" "b" "==" "c"
+}
+
+func ExampleTrie_Freeze() {
+	frozen := runetrie.Must(runetrie.NewCaseInsensitiveTrie("application/json", "application/xml", "text/html")).Freeze()
+	fmt.Println(frozen.MatchAny("Text/HTML"))
+	fmt.Println(frozen.LongestMatchPrefixOf("Application/JSON; charset=utf-8"))
+	// Output:
+	// true
+	// application/json true
+}
+
+func ExampleTrie_MarshalBinary() {
+	data, err := runetrie.Must(runetrie.NewCaseInsensitiveTrie("Foo", "Bar")).MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+
+	var trie runetrie.Trie[string]
+	if err := trie.UnmarshalBinary(data); err != nil {
+		panic(err)
+	}
+	fmt.Println(trie.LongestMatchPrefixOf("FOOBAR"))
+	// Output:
+	// Foo true
+}
+
+func ExampleTrie_Minimize() {
+	dawg := runetrie.NewTrie("talk", "talked", "talking", "walk", "walked", "walking").Minimize()
+	fmt.Println(dawg.LongestMatchPrefixOf("walkings"))
+	fmt.Println(dawg.Index("walked"))
+	fmt.Println(dawg.Len())
+	// Output:
+	// walking true
+	// 4 true
+	// 6
+}
+
+func ExampleBuilder_InsertFrom() {
+	b := runetrie.NewBuilder[string]()
+	if err := b.InsertFrom(bufio.NewScanner(strings.NewReader("station\nstationary\nstations\n"))); err != nil {
+		panic(err)
+	}
+
+	dawg := b.Finish()
+	fmt.Println(dawg.LongestMatchPrefixOf("stationery"))
+	// Output:
+	// station true
+}
+
+func ExampleReadTrie() {
+	const words = `# MIME types
+application/json
+Text/HTML
+`
+	trie, err := runetrie.ReadTrie[string](strings.NewReader(words), runetrie.WithCaseInsensitive())
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(trie.LongestMatchPrefixOf("text/html; charset=utf-8"))
+	// Output:
+	// Text/HTML true
+}
+
+func ExampleWithConflictPolicy() {
+	trie := runetrie.NewTrieWithOptions[string](runetrie.WithCaseInsensitive(), runetrie.WithConflictPolicy(runetrie.ConflictReplace))
+	if err := trie.Add("Foo", "FOO"); err != nil {
+		panic(err)
+	}
+	fmt.Println(trie.MatchPrefixOf("foobar"))
+	// Output:
+	// FOO true
+}
+
+func ExampleWithNormalization() {
+	trie := runetrie.NewTrieWithOptions[string](runetrie.WithNormalization(norm.NFC))
+	if err := trie.Add("caf\u00e9"); err != nil {
+		panic(err)
+	}
+
+	// "e" followed by U+0301 COMBINING ACUTE ACCENT, as in the NFD file names on macOS
+	for end, key := range trie.AllPrefixesOf("cafe\u0301.txt") {
+		fmt.Println(end, key)
+	}
+	// Output:
+	// 6 café
+}
+
+func ExampleWithRuneMapper() {
+	stripAccents := runetrie.RuneMapperFunc(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	})
+	trie := runetrie.NewTrieWithOptions[string](runetrie.WithNormalization(norm.NFD), runetrie.WithRuneMapper(stripAccents))
+	if err := trie.Add("résumé"); err != nil {
+		panic(err)
+	}
+	fmt.Println(trie.MatchPrefixOf("resume.pdf"))
+	// Output:
+	// résumé true
+}
+
+func ExampleWithWidthInsensitive() {
+	trie := runetrie.NewTrieWithOptions[string](runetrie.WithWidthInsensitive(), runetrie.WithKanaInsensitive())
+	if err := trie.Add("がっこう"); err != nil {
+		panic(err)
+	}
+	for end, key := range trie.AllPrefixesOf("ｶﾞｯｺｳ へ") {
+		fmt.Println(end, key)
+	}
+	// Output:
+	// 15 がっこう
+}
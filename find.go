@@ -0,0 +1,83 @@
+package runetrie
+
+import "unicode/utf8"
+
+// FindAllIndex returns the byte offsets of the successive non-overlapping occurrences of the strings in the Trie
+// found in the given text, like regexp.Regexp.FindAllIndex with leftmost-longest semantics:
+// the leftmost occurrence wins, and the longest string wins among the ones starting at the same offset.
+// If n >= 0, it returns at most n occurrences. It returns nil if there is no occurrence.
+func (t *Trie[T]) FindAllIndex(text T, n int) [][2]int {
+	return t.findAll(text, n, t.longestPrefix)
+}
+
+// FindAllString returns the successive non-overlapping occurrences found by FindAllIndex.
+// In case insensitive mode, the occurrences keep the casing of the text.
+// If n >= 0, it returns at most n occurrences. It returns nil if there is no occurrence.
+func (t *Trie[T]) FindAllString(text T, n int) []T {
+	return substrings(text, t.FindAllIndex(text, n))
+}
+
+// FindAllFirstIndex is like FindAllIndex, but with leftmost-first semantics,
+// as if the strings in the Trie were an alternation in the order of All.
+// Since all the strings which occur at the same offset are prefixes of each other, the shortest one wins.
+// If n >= 0, it returns at most n occurrences. It returns nil if there is no occurrence.
+func (t *Trie[T]) FindAllFirstIndex(text T, n int) [][2]int {
+	return t.findAll(text, n, t.shortestPrefix)
+}
+
+// FindAllFirstString returns the successive non-overlapping occurrences found by FindAllFirstIndex.
+// In case insensitive mode, the occurrences keep the casing of the text.
+// If n >= 0, it returns at most n occurrences. It returns nil if there is no occurrence.
+func (t *Trie[T]) FindAllFirstString(text T, n int) []T {
+	return substrings(text, t.FindAllFirstIndex(text, n))
+}
+
+// findAll collects at most n occurrences found by scan with the given prefix matcher.
+func (t *Trie[T]) findAll(text T, n int, match func(T) (*Trie[T], int)) [][2]int {
+	if n == 0 {
+		return nil
+	}
+
+	var result [][2]int
+	t.scan(text, match, func(start, end int, _ *Trie[T]) bool {
+		result = append(result, [2]int{start, end})
+		return len(result) != n
+	})
+	return result
+}
+
+// scan calls fn with the successive non-overlapping occurrences found by the given prefix matcher in the text.
+// As with regexp, an empty occurrence abutting a preceding occurrence is ignored.
+// It stops when fn returns false.
+func (t *Trie[T]) scan(text T, match func(T) (*Trie[T], int), fn func(start, end int, leaf *Trie[T]) bool) {
+	prevEnd := -1
+	for pos := 0; pos <= len(text); {
+		if leaf, end := match(text[pos:]); leaf != nil && (end > 0 || pos != prevEnd) {
+			if !fn(pos, pos+end, leaf) {
+				return
+			}
+			prevEnd = pos + end
+			if end > 0 {
+				pos += end
+				continue
+			}
+		}
+		if pos == len(text) {
+			return
+		}
+		_, size := utf8.DecodeRuneInString(string(text[pos:]))
+		pos += size
+	}
+}
+
+func substrings[T ~string](text T, indices [][2]int) []T {
+	if indices == nil {
+		return nil
+	}
+
+	result := make([]T, len(indices))
+	for i, index := range indices {
+		result[i] = text[index[0]:index[1]]
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package runetrie_test
+
+import (
+	"math/rand"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k0kubun/pp"
+	"github.com/karupanerura/runetrie"
+)
+
+func Test_Trie_FindAllString(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     []string
+		text    string
+		n       int
+		longest []string
+		first   []string
+	}{
+		{
+			name:    "Empty",
+			set:     []string{},
+			text:    "foo",
+			n:       -1,
+			longest: nil,
+			first:   nil,
+		},
+		{
+			name:    "Alternation",
+			set:     []string{"foo", "foobar", "baz"},
+			text:    "foobarbaz foo fooba",
+			n:       -1,
+			longest: []string{"foobar", "baz", "foo", "foo"},
+			first:   []string{"foo", "baz", "foo", "foo"},
+		},
+		{
+			name:    "Limit",
+			set:     []string{"foo", "foobar", "baz"},
+			text:    "foobarbaz foo fooba",
+			n:       2,
+			longest: []string{"foobar", "baz"},
+			first:   []string{"foo", "baz"},
+		},
+		{
+			name:    "NonOverlapping",
+			set:     []string{"aa"},
+			text:    "aaaaa",
+			n:       -1,
+			longest: []string{"aa", "aa"},
+			first:   []string{"aa", "aa"},
+		},
+		{
+			name:    "MultiByte",
+			set:     []string{"東京", "東京都", "京都"},
+			text:    "東京都と京都",
+			n:       -1,
+			longest: []string{"東京都", "京都"},
+			first:   []string{"東京", "京都"},
+		},
+		{
+			name:    "EmptyEntry",
+			set:     []string{"", "b"},
+			text:    "abc",
+			n:       -1,
+			longest: []string{"", "b", ""},
+			first:   []string{"", "", "", ""},
+		},
+		{
+			name:    "CaseSensitive",
+			set:     []string{"foo"},
+			text:    "FOO",
+			n:       -1,
+			longest: nil,
+			first:   nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrie(tt.set...)
+			if diff := cmp.Diff(tt.longest, tr.FindAllString(tt.text, tt.n)); diff != "" {
+				t.Errorf("Trie.FindAllString() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tr))
+			}
+			if diff := cmp.Diff(tt.first, tr.FindAllFirstString(tt.text, tt.n)); diff != "" {
+				t.Errorf("Trie.FindAllFirstString() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tr))
+			}
+		})
+	}
+}
+
+func Test_CaseInsensitiveTrie_FindAllString(t *testing.T) {
+	tr := runetrie.Must(runetrie.NewCaseInsensitiveTrie("foo", "FooBar", "BAZ"))
+	if diff := cmp.Diff([]string{"FOOBAR", "baz", "Foo"}, tr.FindAllString("FOOBARbaz Foo", -1)); diff != "" {
+		t.Errorf("Trie.FindAllString() mismatch.\n%s", diff)
+	}
+	if diff := cmp.Diff([][2]int{{0, 3}, {6, 9}, {10, 13}}, tr.FindAllFirstIndex("FOOBARbaz Foo", -1)); diff != "" {
+		t.Errorf("Trie.FindAllFirstIndex() mismatch.\n%s", diff)
+	}
+}
+
+func TestFindAllIndex_Regexp(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	alphabet := []rune("abAB√")
+	for i := 0; i < 200; i++ {
+		set := make([]string, 1+rnd.Intn(8))
+		for j := range set {
+			set[j] = randomString(rnd, alphabet, 4)
+		}
+		text := randomString(rnd, alphabet, 32)
+
+		for _, tt := range []struct {
+			trie  *runetrie.Trie[string]
+			flags string
+		}{
+			{runetrie.NewTrie(set...), ""},
+			{caseInsensitiveTrieOf(set...), "(?i)"},
+		} {
+			// the alternation in the order of All is the leftmost-first oracle
+			var alternation []string
+			for s := range tt.trie.All() {
+				alternation = append(alternation, regexp.QuoteMeta(s))
+			}
+			first := regexp.MustCompile(tt.flags + "(?:" + strings.Join(alternation, "|") + ")")
+			longest := regexp.MustCompile(first.String())
+			longest.Longest()
+
+			n := rnd.Intn(5) - 1
+			if diff := cmp.Diff(toPairs(longest.FindAllStringIndex(text, n)), tt.trie.FindAllIndex(text, n)); diff != "" {
+				t.Fatalf("Trie.FindAllIndex(%q, %d) with %q mismatch.\n%s", text, n, set, diff)
+			}
+			if diff := cmp.Diff(toPairs(first.FindAllStringIndex(text, n)), tt.trie.FindAllFirstIndex(text, n)); diff != "" {
+				t.Fatalf("Trie.FindAllFirstIndex(%q, %d) with %q mismatch.\n%s", text, n, set, diff)
+			}
+		}
+	}
+}
+
+func toPairs(indices [][]int) [][2]int {
+	if indices == nil {
+		return nil
+	}
+	return slices.Collect(func(yield func([2]int) bool) {
+		for _, index := range indices {
+			if !yield([2]int{index[0], index[1]}) {
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package runetrie
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// foldRune returns the representative rune of the case folded aliases which Add registers for the given rune,
+// which is the smallest rune in the case folding orbit, such as "K" for "k" and "\u212A" (Kelvin sign).
+// The runes without any other case, such as "İ" (U+0130), are the representatives of their own.
+func foldRune(c rune) rune {
+	if c < utf8.RuneSelf {
+		if 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		return c
+	}
+
+	smallest := c
+	for r := unicode.SimpleFold(c); r != c; r = unicode.SimpleFold(r) {
+		smallest = min(smallest, r)
+	}
+	return smallest
+}
+
+// foldString returns the string with the runes replaced by foldRune in case insensitive mode.
+// As with the lookups, each byte of the invalid UTF-8 sequences is replaced by utf8.RuneError.
+func foldString(s string, caseInsensitive bool) string {
+	if !caseInsensitive {
+		return strings.Map(func(c rune) rune { return c }, s)
+	}
+	return strings.Map(foldRune, s)
+}
+
+// runeWidths returns the minimum and maximum byte lengths of the input which is decoded as the given rune.
+// utf8.RuneError is decoded from both an invalid byte and its own 3 bytes encoding.
+func runeWidths(c rune) (int, int) {
+	if c == utf8.RuneError {
+		return 1, utf8.RuneLen(c)
+	}
+	n := utf8.RuneLen(c)
+	return n, n
+}
+
+// foldedWidths returns the minimum and maximum byte lengths of the input which matches the given rune.
+// In case insensitive mode, they cover all the runes in the case folding orbit,
+// for example, "k" is 1 byte and "K" (Kelvin sign) is 3 bytes.
+func foldedWidths(c rune, caseInsensitive bool) (int, int) {
+	lo, hi := runeWidths(c)
+	if caseInsensitive {
+		for r := unicode.SimpleFold(c); r != c; r = unicode.SimpleFold(r) {
+			n := utf8.RuneLen(r)
+			lo, hi = min(lo, n), max(hi, n)
+		}
+	}
+	return lo, hi
+}
+
+// foldedLen returns the minimum and maximum byte lengths of the input which matches the given string.
+func foldedLen(s string, caseInsensitive bool) (int, int) {
+	var lo, hi int
+	for _, c := range s {
+		l, h := foldedWidths(c, caseInsensitive)
+		lo, hi = lo+l, hi+h
+	}
+	return lo, hi
+}
@@ -0,0 +1,75 @@
+package runetrie_test
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestCaseInsensitive_EqualFold checks that the case insensitive lookups agree with a brute-force strings.EqualFold oracle,
+// especially with the case folding orbits whose runes have different byte lengths.
+func TestCaseInsensitive_EqualFold(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	alphabet := []rune("aikKKsSſσΣςßẞǄǅǆİı")
+	for i := 0; i < 500; i++ {
+		set := make([]string, rnd.Intn(16))
+		for j := range set {
+			set[j] = randomString(rnd, alphabet, 5)
+		}
+		tr := caseInsensitiveTrieOf(set...)
+
+		targets := make([]string, 16)
+		for j := range targets {
+			targets[j] = randomString(rnd, alphabet, 7)
+		}
+
+		oracle := prefixOracle{keys: slices.Collect(tr.All()), equal: strings.EqualFold}
+		assertLookup(t, "Trie", tr, oracle, targets)
+		assertLookup(t, "FrozenTrie", tr.Freeze(), oracle, targets)
+		assertLookup(t, "DAWG", tr.Minimize(), oracle, targets)
+	}
+}
+
+// prefixOracle answers the lookups by brute force over the keys, comparing them with equal.
+type prefixOracle struct {
+	keys  []string
+	equal func(a, b string) bool
+}
+
+func (o prefixOracle) MatchAny(s string) bool {
+	return slices.ContainsFunc(o.keys, func(key string) bool { return o.equal(key, s) })
+}
+
+func (o prefixOracle) MatchAnyPrefixOf(s string) bool {
+	_, matched := o.MatchPrefixOf(s)
+	return matched
+}
+
+func (o prefixOracle) MatchPrefixOf(s string) (string, bool) {
+	for _, end := range runeEnds(s) {
+		if i := slices.IndexFunc(o.keys, func(key string) bool { return o.equal(key, s[:end]) }); i != -1 {
+			return o.keys[i], true
+		}
+	}
+	return "", false
+}
+
+func (o prefixOracle) LongestMatchPrefixOf(s string) (string, bool) {
+	for _, end := range slices.Backward(runeEnds(s)) {
+		if i := slices.IndexFunc(o.keys, func(key string) bool { return o.equal(key, s[:end]) }); i != -1 {
+			return o.keys[i], true
+		}
+	}
+	return "", false
+}
+
+// runeEnds returns the byte offsets of s where a rune ends, beginning with 0.
+func runeEnds(s string) []int {
+	ends := []int{0}
+	for i, c := range s {
+		ends = append(ends, i+len(string(c)))
+	}
+	return ends
+}
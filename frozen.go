@@ -0,0 +1,271 @@
+package runetrie
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// FrozenTrie is an immutable and compact representation of a Trie.
+// It is built by Trie.Freeze and answers the same queries as the Trie with the same results.
+// It stores the strings in a double-array (BASE/CHECK) over the UTF-8 bytes instead of a map per node,
+// so it is much smaller and puts much less pressure on the garbage collector with a large number of strings.
+// It is safe for concurrent use.
+type FrozenTrie[T ~string] struct {
+	i bool // case insensitive
+	l struct {
+		max, min int
+	}
+	base  []int32 // the offset of the children, or the negated index of the string plus one for the terminals
+	check []int32 // the parent of the node, or -1 for the free cells
+	keys  string  // the concatenated strings
+	offs  []uint32
+
+	mapped bool // the arrays and the strings refer to the memory mapped by LoadMapped
+}
+
+// the node code of the terminal, and the node codes of the bytes are the bytes plus one.
+const frozenTerminalCode = 0
+
+// Freeze builds a FrozenTrie from the strings in the Trie.
+// The FrozenTrie is a snapshot of the Trie at the time of Freeze, so it is not affected by later changes to the Trie.
+// It panics if the Trie is normalized by WithNormalization, WithRuneMapper, WithWidthInsensitive or WithKanaInsensitive.
+func (t *Trie[T]) Freeze() *FrozenTrie[T] {
+	t.mustNotBeNormalized("Freeze")
+	type entry struct {
+		folded string
+		index  int
+	}
+
+	var sb strings.Builder
+	var entries []entry
+	offs := []uint32{0}
+	for s := range t.All() {
+		entries = append(entries, entry{folded: foldString(string(s), t.i), index: len(offs) - 1})
+		sb.WriteString(string(s))
+		offs = append(offs, uint32(sb.Len()))
+	}
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		return strings.Compare(a.folded, b.folded)
+	})
+	entries = slices.CompactFunc(entries, func(a, b entry) bool {
+		return a.folded == b.folded
+	})
+
+	ft := &FrozenTrie[T]{i: t.i, keys: sb.String(), offs: offs}
+	ft.l = t.l
+
+	b := &doubleArrayBuilder{base: []int32{0}, check: []int32{-1}, free: 1}
+	folded := make([]string, len(entries))
+	indices := make([]int32, len(entries))
+	for i, e := range entries {
+		folded[i], indices[i] = e.folded, int32(e.index)
+	}
+	if len(folded) != 0 {
+		b.build(0, folded, indices, 0)
+	}
+	ft.base, ft.check = b.base, b.check
+	return ft
+}
+
+// MatchAny checks if any of the strings in the FrozenTrie match the given string.
+// It returns true if there is a match, false otherwise.
+func (ft *FrozenTrie[T]) MatchAny(s T) bool {
+	if len(s) < ft.l.min || ft.l.max < len(s) {
+		return false
+	}
+
+	node := int32(0)
+	for _, c := range s {
+		if node = ft.step(node, c); node < 0 {
+			return false
+		}
+	}
+	return ft.terminal(node) >= 0
+}
+
+// MatchAnyPrefixOf checks if any of the strings in the FrozenTrie match any prefix of the given string.
+// It returns true if there is a match, false otherwise.
+func (ft *FrozenTrie[T]) MatchAnyPrefixOf(s T) bool {
+	return ft.shortestPrefix(s) >= 0
+}
+
+// MatchPrefixOf checks if the given string's prefix matches any of the strings in the FrozenTrie.
+// It returns the shortest matched string and true if there is a match, or an empty string and false otherwise.
+func (ft *FrozenTrie[T]) MatchPrefixOf(s T) (T, bool) {
+	if k := ft.shortestPrefix(s); k >= 0 {
+		return ft.key(k), true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// LongestMatchPrefixOf checks if the given string's prefix matches any of the strings in the FrozenTrie.
+// It returns the longest matched string and true if there is a match, or an empty string and false otherwise.
+func (ft *FrozenTrie[T]) LongestMatchPrefixOf(s T) (T, bool) {
+	if len(s) < ft.l.min {
+		var zero T
+		return zero, false
+	}
+	if len(s) > ft.l.max {
+		s = s[:ft.l.max]
+	}
+
+	result := ft.terminal(0)
+	node := int32(0)
+	for _, c := range s {
+		if node = ft.step(node, c); node < 0 {
+			break
+		}
+		if k := ft.terminal(node); k >= 0 {
+			result = k
+		}
+	}
+	if result >= 0 {
+		return ft.key(result), true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// shortestPrefix returns the index of the shortest string in the FrozenTrie which is a prefix of the given string,
+// or -1 if there is no match.
+func (ft *FrozenTrie[T]) shortestPrefix(s T) int32 {
+	if len(s) < ft.l.min {
+		return -1
+	}
+	if len(s) > ft.l.max {
+		s = s[:ft.l.max]
+	}
+	if k := ft.terminal(0); k >= 0 {
+		return k
+	}
+
+	node := int32(0)
+	for _, c := range s {
+		if node = ft.step(node, c); node < 0 {
+			return -1
+		}
+		if k := ft.terminal(node); k >= 0 {
+			return k
+		}
+	}
+	return -1
+}
+
+// step returns the node reached from the given node by the UTF-8 bytes of the given rune, or -1 if there is none.
+func (ft *FrozenTrie[T]) step(node int32, c rune) int32 {
+	if ft.i {
+		c = foldRune(c)
+	}
+	if c < utf8.RuneSelf {
+		return ft.next(node, byte(c))
+	}
+
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], c)
+	for _, b := range buf[:n] {
+		if node = ft.next(node, b); node < 0 {
+			return -1
+		}
+	}
+	return node
+}
+
+// next returns the child of the given node by the given byte, or -1 if there is none.
+func (ft *FrozenTrie[T]) next(node int32, b byte) int32 {
+	child := ft.base[node] + int32(b) + 1
+	if uint32(child) >= uint32(len(ft.check)) || ft.check[child] != node {
+		return -1
+	}
+	return child
+}
+
+// terminal returns the index of the string which terminates at the given node, or -1 if there is none.
+func (ft *FrozenTrie[T]) terminal(node int32) int32 {
+	child := ft.base[node] + frozenTerminalCode
+	if uint32(child) >= uint32(len(ft.check)) || ft.check[child] != node {
+		return -1
+	}
+	return -ft.base[child] - 1
+}
+
+func (ft *FrozenTrie[T]) key(k int32) T {
+	s := ft.keys[ft.offs[k]:ft.offs[k+1]]
+	if ft.mapped {
+		// the mapped memory is not valid after Close
+		return T(strings.Clone(s))
+	}
+	return T(s)
+}
+
+// doubleArrayBuilder places the nodes of sorted strings into a double-array.
+type doubleArrayBuilder struct {
+	base, check []int32
+	free        int // the hint of the first free cell
+}
+
+// build places the children of the given node, which is shared by the given strings up to the given depth.
+func (b *doubleArrayBuilder) build(node int32, keys []string, indices []int32, depth int) {
+	var codes []int32
+	for _, key := range keys {
+		code := int32(frozenTerminalCode)
+		if len(key) > depth {
+			code = int32(key[depth]) + 1
+		}
+		if len(codes) == 0 || codes[len(codes)-1] != code {
+			codes = append(codes, code)
+		}
+	}
+
+	base := b.findBase(codes)
+	b.base[node] = base
+	for _, code := range codes {
+		b.check[base+code] = node
+	}
+
+	for lo := 0; lo < len(keys); {
+		if len(keys[lo]) == depth {
+			b.base[base+frozenTerminalCode] = -indices[lo] - 1
+			lo++
+			continue
+		}
+
+		hi := lo + 1
+		for hi < len(keys) && keys[hi][depth] == keys[lo][depth] {
+			hi++
+		}
+		b.build(base+int32(keys[lo][depth])+1, keys[lo:hi], indices[lo:hi], depth+1)
+		lo = hi
+	}
+}
+
+// findBase returns the smallest base at which all the cells for the given codes are free.
+func (b *doubleArrayBuilder) findBase(codes []int32) int32 {
+	for b.free < len(b.check) && b.check[b.free] >= 0 {
+		b.free++
+	}
+
+	for base := max(int32(b.free)-codes[0], 1); ; base++ {
+		b.grow(int(base + codes[len(codes)-1] + 1))
+		ok := true
+		for _, code := range codes {
+			if b.check[base+code] >= 0 {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return base
+		}
+	}
+}
+
+func (b *doubleArrayBuilder) grow(n int) {
+	for len(b.check) < n {
+		b.base = append(b.base, 0)
+		b.check = append(b.check, -1)
+	}
+}
@@ -0,0 +1,117 @@
+package runetrie_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/runetrie"
+)
+
+func Test_FrozenTrie(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     []string
+		targets []string
+	}{
+		{
+			name:    "Empty",
+			set:     []string{},
+			targets: []string{"", "foo"},
+		},
+		{
+			name:    "EmptyEntry",
+			set:     []string{"", "A"},
+			targets: []string{"", "A", "AB", "B"},
+		},
+		{
+			name:    "Prefixes",
+			set:     []string{"A", "AA", "AAA", "ABCA"},
+			targets: []string{"", "A", "AA", "AAC", "AAAA", "ABC", "ABCABC", "abca"},
+		},
+		{
+			name:    "MultiByte",
+			set:     []string{"東", "東京", "東京都", "京都", "éa", "aé"},
+			targets: []string{"東京都庁", "京", "京都", "éa", "aé", "ÉA"},
+		},
+		{
+			name:    "InvalidUTF8",
+			set:     []string{"a\xff", "\xe3\x81"},
+			targets: []string{"a\xff", "a\xfe", "\xe3\x81\x81", "a�"},
+		},
+		{
+			name:    "CaseFolding",
+			set:     []string{"Foo", "BAR", "ÀB", "ΣΑΣ"},
+			targets: []string{"foo", "FOOBAR", "bar", "àb", "σασ", "ΣΑς"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assertFrozenTrie(t, runetrie.NewTrie(tt.set...), tt.targets)
+			assertFrozenTrie(t, caseInsensitiveTrieOf(tt.set...), tt.targets)
+		})
+	}
+}
+
+func TestFrozenTrie_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	alphabet := []rune("abAB√あ")
+	for i := 0; i < 200; i++ {
+		set := make([]string, rnd.Intn(32))
+		for j := range set {
+			set[j] = randomString(rnd, alphabet, 6)
+		}
+		targets := make([]string, 32)
+		for j := range targets {
+			targets[j] = randomString(rnd, alphabet, 8)
+		}
+
+		assertFrozenTrie(t, runetrie.NewTrie(set...), targets)
+		assertFrozenTrie(t, caseInsensitiveTrieOf(set...), targets)
+	}
+}
+
+func assertFrozenTrie(t *testing.T, tr *runetrie.Trie[string], targets []string) {
+	t.Helper()
+	assertLookup(t, "FrozenTrie", tr.Freeze(), tr, targets)
+}
+
+// lookup is the queries which Trie, FrozenTrie, MappedTrie and DAWG answer in the same way.
+type lookup interface {
+	MatchAny(string) bool
+	MatchAnyPrefixOf(string) bool
+	MatchPrefixOf(string) (string, bool)
+	LongestMatchPrefixOf(string) (string, bool)
+}
+
+// assertLookup asserts that the lookup named name answers the same results as the ref.
+func assertLookup(t *testing.T, name string, l, ref lookup, targets []string) {
+	t.Helper()
+
+	type ret struct {
+		Result  string
+		Matched bool
+	}
+	for _, target := range targets {
+		if got, want := l.MatchAny(target), ref.MatchAny(target); got != want {
+			t.Errorf("%s.MatchAny(%q) = %v, want %v", name, target, got, want)
+		}
+		if got, want := l.MatchAnyPrefixOf(target), ref.MatchAnyPrefixOf(target); got != want {
+			t.Errorf("%s.MatchAnyPrefixOf(%q) = %v, want %v", name, target, got, want)
+		}
+
+		result, matched := l.MatchPrefixOf(target)
+		wantResult, wantMatched := ref.MatchPrefixOf(target)
+		if diff := cmp.Diff(ret{wantResult, wantMatched}, ret{result, matched}); diff != "" {
+			t.Errorf("%s.MatchPrefixOf(%q) mismatch.\n%s", name, target, diff)
+		}
+
+		result, matched = l.LongestMatchPrefixOf(target)
+		wantResult, wantMatched = ref.LongestMatchPrefixOf(target)
+		if diff := cmp.Diff(ret{wantResult, wantMatched}, ret{result, matched}); diff != "" {
+			t.Errorf("%s.LongestMatchPrefixOf(%q) mismatch.\n%s", name, target, diff)
+		}
+	}
+}
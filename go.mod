@@ -1,10 +1,11 @@
 module github.com/karupanerura/runetrie
 
-go 1.22
+go 1.23.0
 
 require (
 	github.com/google/go-cmp v0.6.0
 	github.com/k0kubun/pp v3.0.1+incompatible
+	golang.org/x/text v0.28.0
 )
 
 require github.com/k0kubun/colorstring v0.0.0-20150214042306-9440f1994b88 // indirect
@@ -0,0 +1,128 @@
+package runetrie
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// All returns an iterator over all the strings in the Trie in lexicographic rune order.
+// In case insensitive mode, the strings are ordered by their case folded forms,
+// which is the same order as the indices of DAWG and the input of NewCaseInsensitiveBuilder,
+// and each string is yielded once with its original casing.
+func (t *Trie[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.forward(t.i, yield)
+	}
+}
+
+// Backward returns an iterator over all the strings in the Trie in reverse order of All.
+func (t *Trie[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		t.backward(t.i, yield)
+	}
+}
+
+func (t *Trie[T]) forward(dedup bool, yield func(T) bool) bool {
+	if t.e && !yield(t.s) {
+		return false
+	}
+	for _, leaf := range t.children(dedup) {
+		if !leaf.forward(dedup, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *Trie[T]) backward(dedup bool, yield func(T) bool) bool {
+	children := t.children(dedup)
+	for i := len(children) - 1; i >= 0; i-- {
+		if !children[i].backward(dedup, yield) {
+			return false
+		}
+	}
+	return !t.e || yield(t.s)
+}
+
+// children returns the child nodes ordered by their runes.
+// If dedup is true, the nodes shared by the case folded aliases are returned only once, ordered by foldRune.
+func (t *Trie[T]) children(dedup bool) []*Trie[T] {
+	if len(t.m) == 0 {
+		return nil
+	}
+
+	runes := make([]rune, 0, len(t.m))
+	for c := range t.m {
+		runes = append(runes, c)
+	}
+	slices.Sort(runes)
+
+	children := make([]*Trie[T], 0, len(runes))
+	if !dedup {
+		for _, c := range runes {
+			children = append(children, t.m[c])
+		}
+		return children
+	}
+
+	slices.SortFunc(runes, func(a, b rune) int {
+		return cmp.Or(cmp.Compare(foldRune(a), foldRune(b)), cmp.Compare(a, b))
+	})
+	seen := make(map[*Trie[T]]struct{}, len(runes))
+	for _, c := range runes {
+		leaf := t.m[c]
+		if _, ok := seen[leaf]; ok {
+			continue
+		}
+		seen[leaf] = struct{}{}
+		children = append(children, leaf)
+	}
+	return children
+}
+
+// KeysWithPrefix returns an iterator over all the strings in the Trie which start with the given prefix,
+// in the same order as All.
+// In case insensitive mode, the prefix is matched in a case insensitive manner
+// and the strings are yielded with their original casing.
+func (t *Trie[T]) KeysWithPrefix(p T) iter.Seq[T] {
+	return t.KeysWithPrefixN(p, -1)
+}
+
+// KeysWithPrefixN is like KeysWithPrefix, but it yields at most n strings.
+// If n is negative, there is no limit.
+func (t *Trie[T]) KeysWithPrefixN(p T, n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n == 0 {
+			return
+		}
+
+		tree, _ := t.walk(t.key(p))
+		if tree == nil {
+			return
+		}
+		if n < 0 {
+			tree.forward(t.i, yield)
+			return
+		}
+
+		rest := n
+		tree.forward(t.i, func(s T) bool {
+			rest--
+			return yield(s) && rest > 0
+		})
+	}
+}
+
+// AllPrefixesOf returns an iterator over all the strings in the Trie which are a prefix of the given string,
+// from the shortest to the longest, together with the byte offset in s where each match ends.
+// In case insensitive mode, the offsets are in terms of s rather than the matched strings.
+func (t *Trie[T]) AllPrefixesOf(s T) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for end, leaf := range t.prefixes(s) {
+			if !yield(end, leaf.s) {
+				return
+			}
+		}
+	}
+}
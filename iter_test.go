@@ -0,0 +1,310 @@
+package runetrie_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k0kubun/pp"
+	"github.com/karupanerura/runetrie"
+)
+
+func Test_Trie_All(t *testing.T) {
+	tests := []struct {
+		name string
+		set  []string
+		want []string
+	}{
+		{
+			name: "Empty",
+			set:  []string{},
+			want: nil,
+		},
+		{
+			name: "EmptyEntry",
+			set:  []string{""},
+			want: []string{""},
+		},
+		{
+			name: "Lexicographic",
+			set:  []string{"b", "abc", "a", "", "ab", "B", "ac"},
+			want: []string{"", "B", "a", "ab", "abc", "ac", "b"},
+		},
+		{
+			name: "MultiByte",
+			set:  []string{"あい", "あ", "い", "z"},
+			want: []string{"z", "あ", "あい", "い"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrie(tt.set...)
+			if diff := cmp.Diff(tt.want, slices.Collect(tr.All())); diff != "" {
+				t.Errorf("Trie.All() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tr))
+			}
+
+			want := slices.Clone(tt.want)
+			slices.Reverse(want)
+			if diff := cmp.Diff(want, slices.Collect(tr.Backward())); diff != "" {
+				t.Errorf("Trie.Backward() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tr))
+			}
+		})
+	}
+}
+
+func Test_CaseInsensitiveTrie_All(t *testing.T) {
+	tests := []struct {
+		name string
+		set  []string
+		want []string
+	}{
+		{
+			name: "Empty",
+			set:  []string{},
+			want: nil,
+		},
+		{
+			name: "OriginalCasing",
+			set:  []string{"Foo", "bar", "BAZ", "foobar"},
+			want: []string{"bar", "BAZ", "Foo", "foobar"},
+		},
+		{
+			name: "Symbols",
+			set:  []string{"a!", "A", "_"},
+			want: []string{"A", "a!", "_"},
+		},
+		{
+			name: "FoldedOrder",
+			set:  []string{"_a", "a", "ΣΑΣ", "ſ"},
+			want: []string{"a", "ſ", "_a", "ΣΑΣ"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.Must(runetrie.NewCaseInsensitiveTrie(tt.set...))
+			if diff := cmp.Diff(tt.want, slices.Collect(tr.All())); diff != "" {
+				t.Errorf("Trie.All() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tr))
+			}
+
+			want := slices.Clone(tt.want)
+			slices.Reverse(want)
+			if diff := cmp.Diff(want, slices.Collect(tr.Backward())); diff != "" {
+				t.Errorf("Trie.Backward() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tr))
+			}
+		})
+	}
+}
+
+func TestAll_Break(t *testing.T) {
+	tr := runetrie.NewTrie("a", "b", "c")
+	var got []string
+	for s := range tr.All() {
+		got = append(got, s)
+		if s == "b" {
+			break
+		}
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, got); diff != "" {
+		t.Errorf("Trie.All() must stop on break.\n%s", diff)
+	}
+}
+
+func Test_Trie_KeysWithPrefixN(t *testing.T) {
+	set := []string{"", "app", "apple", "application/json", "Application/XML", "banana"}
+	tests := []struct {
+		name   string
+		prefix string
+		n      int
+		want   []string
+	}{
+		{
+			name:   "EmptyPrefix",
+			prefix: "",
+			n:      -1,
+			want:   []string{"", "Application/XML", "app", "apple", "application/json", "banana"},
+		},
+		{
+			name:   "Prefix",
+			prefix: "app",
+			n:      -1,
+			want:   []string{"app", "apple", "application/json"},
+		},
+		{
+			name:   "Limit",
+			prefix: "app",
+			n:      2,
+			want:   []string{"app", "apple"},
+		},
+		{
+			name:   "Zero",
+			prefix: "app",
+			n:      0,
+			want:   nil,
+		},
+		{
+			name:   "NotFound",
+			prefix: "apq",
+			n:      -1,
+			want:   nil,
+		},
+		{
+			name:   "CaseSensitive",
+			prefix: "APP",
+			n:      -1,
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrie(set...)
+			if diff := cmp.Diff(tt.want, slices.Collect(tr.KeysWithPrefixN(tt.prefix, tt.n))); diff != "" {
+				t.Errorf("Trie.KeysWithPrefixN() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tr))
+			}
+		})
+	}
+}
+
+func Test_CaseInsensitiveTrie_KeysWithPrefixN(t *testing.T) {
+	set := []string{"text/plain", "Application/JSON", "application/xml", "Apple"}
+	tests := []struct {
+		name   string
+		prefix string
+		n      int
+		want   []string
+	}{
+		{
+			name:   "Prefix",
+			prefix: "app",
+			n:      -1,
+			want:   []string{"Apple", "Application/JSON", "application/xml"},
+		},
+		{
+			name:   "Limit",
+			prefix: "APPLICATION/",
+			n:      1,
+			want:   []string{"Application/JSON"},
+		},
+		{
+			name:   "NotFound",
+			prefix: "image/",
+			n:      -1,
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.Must(runetrie.NewCaseInsensitiveTrie(set...))
+			if diff := cmp.Diff(tt.want, slices.Collect(tr.KeysWithPrefixN(tt.prefix, tt.n))); diff != "" {
+				t.Errorf("Trie.KeysWithPrefixN() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tr))
+			}
+		})
+	}
+}
+
+func Test_Trie_AllPrefixesOf(t *testing.T) {
+	type match struct {
+		End int
+		Key string
+	}
+	tests := []struct {
+		name   string
+		set    []string
+		target string
+		want   []match
+	}{
+		{
+			name:   "Empty",
+			set:    []string{},
+			target: "",
+			want:   nil,
+		},
+		{
+			name:   "EmptyEntry",
+			set:    []string{"", "A"},
+			target: "AB",
+			want:   []match{{0, ""}, {1, "A"}},
+		},
+		{
+			name:   "AllPrefixes",
+			set:    []string{"A", "AA", "AAA", "AB"},
+			target: "AAC",
+			want:   []match{{1, "A"}, {2, "AA"}},
+		},
+		{
+			name:   "MultiByte",
+			set:    []string{"東", "東京", "東京都", "京都"},
+			target: "東京都庁",
+			want:   []match{{3, "東"}, {6, "東京"}, {9, "東京都"}},
+		},
+		{
+			name:   "Mismatch",
+			set:    []string{"AA", "AAA"},
+			target: "ABC",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrie(tt.set...)
+			var got []match
+			for end, key := range tr.AllPrefixesOf(tt.target) {
+				got = append(got, match{end, key})
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Trie.AllPrefixesOf() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tr))
+			}
+		})
+	}
+}
+
+func Test_CaseInsensitiveTrie_AllPrefixesOf(t *testing.T) {
+	type match struct {
+		End int
+		Key string
+	}
+	tests := []struct {
+		name   string
+		set    []string
+		target string
+		want   []match
+	}{
+		{
+			name:   "AllPrefixes",
+			set:    []string{"a", "Ab", "ABC", "b"},
+			target: "abcd",
+			want:   []match{{1, "a"}, {2, "Ab"}, {3, "ABC"}},
+		},
+		{
+			name:   "Mismatch",
+			set:    []string{"a", "Ab", "ABC"},
+			target: "bcd",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.Must(runetrie.NewCaseInsensitiveTrie(tt.set...))
+			var got []match
+			for end, key := range tr.AllPrefixesOf(tt.target) {
+				got = append(got, match{end, key})
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Trie.AllPrefixesOf() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tr))
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package runetrie
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// LineError is an error with the line number where it occurred, returned by ReadTrie and LoadFile.
+type LineError struct {
+	Line int // 1-based
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// ReadOption is an option of ReadTrie and LoadFile.
+// Every Option is also a ReadOption, and WithTrimSpace and WithColumns are only for ReadTrie and LoadFile.
+type ReadOption interface {
+	applyRead(*readOptions)
+}
+
+type readOptions struct {
+	options
+	trimSpace bool
+	columns   func(key string, columns []string) error
+}
+
+func (opt Option) applyRead(o *readOptions) {
+	opt(&o.options)
+}
+
+// readOption is a ReadOption which is not an Option.
+type readOption func(*readOptions)
+
+func (opt readOption) applyRead(o *readOptions) {
+	opt(o)
+}
+
+// WithTrimSpace trims the leading and trailing white spaces of each line.
+func WithTrimSpace() ReadOption {
+	return readOption(func(o *readOptions) {
+		o.trimSpace = true
+	})
+}
+
+// WithColumns splits each line into the tab-separated columns.
+// The first column is added to the Trie as the key, and the given function is called with the key and the rest columns.
+// If the function returns an error, the reading stops with the error.
+func WithColumns(fn func(key string, columns []string) error) ReadOption {
+	return readOption(func(o *readOptions) {
+		o.columns = fn
+	})
+}
+
+// ReadTrie creates a new Trie with the strings read from the given reader, one per line.
+// The blank lines and the comment lines starting with '#' are skipped.
+// The errors on a line, such as ErrConflictEntry in case insensitive mode, are returned as *LineError.
+func ReadTrie[T ~string](r io.Reader, opts ...ReadOption) (*Trie[T], error) {
+	var o readOptions
+	for _, opt := range opts {
+		opt.applyRead(&o)
+	}
+
+	trie := newTrie[T](&o.options)
+
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if o.trimSpace {
+			line = strings.TrimSpace(line)
+		}
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if o.columns == nil {
+			if err := trie.Add(T(line)); err != nil {
+				return nil, &LineError{Line: n, Err: err}
+			}
+			continue
+		}
+
+		columns := strings.Split(line, "\t")
+		if err := trie.Add(T(columns[0])); err != nil {
+			return nil, &LineError{Line: n, Err: err}
+		}
+		if err := o.columns(columns[0], columns[1:]); err != nil {
+			return nil, &LineError{Line: n, Err: err}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return trie, nil
+}
+
+// LoadFile creates a new Trie with the strings read from the file at the given path, one per line.
+// It is the same as ReadTrie except that it reads the file.
+func LoadFile[T ~string](path string, opts ...ReadOption) (*Trie[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadTrie[T](f, opts...)
+}
@@ -0,0 +1,148 @@
+package runetrie_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k0kubun/pp"
+	"github.com/karupanerura/runetrie"
+)
+
+func TestReadTrie(t *testing.T) {
+	type column struct {
+		Key     string
+		Columns []string
+	}
+	var columns []column
+	withColumns := runetrie.WithColumns(func(key string, cs []string) error {
+		columns = append(columns, column{Key: key, Columns: cs})
+		return nil
+	})
+
+	tests := []struct {
+		name        string
+		input       string
+		opts        []runetrie.ReadOption
+		want        []string
+		wantColumns []column
+		wantLine    int
+		wantErr     error
+	}{
+		{
+			name:  "Empty",
+			input: "",
+			want:  nil,
+		},
+		{
+			name:  "Lines",
+			input: "foo\nbar\r\n\nbaz",
+			want:  []string{"bar", "baz", "foo"},
+		},
+		{
+			name:  "Comments",
+			input: "# words\nfoo\n#bar\n foo bar \n",
+			want:  []string{" foo bar ", "foo"},
+		},
+		{
+			name:  "TrimSpace",
+			input: "  foo\t\n   \n  # comment\nbar  \n",
+			opts:  []runetrie.ReadOption{runetrie.WithTrimSpace()},
+			want:  []string{"bar", "foo"},
+		},
+		{
+			name:        "Columns",
+			input:       "foo\t1\tx\nbar\n",
+			opts:        []runetrie.ReadOption{withColumns},
+			want:        []string{"bar", "foo"},
+			wantColumns: []column{{Key: "foo", Columns: []string{"1", "x"}}, {Key: "bar", Columns: []string{}}},
+		},
+		{
+			name:  "CaseInsensitive",
+			input: "Foo\nBAR\n",
+			opts:  []runetrie.ReadOption{runetrie.WithCaseInsensitive()},
+			want:  []string{"BAR", "Foo"},
+		},
+		{
+			name:  "ConflictPolicy",
+			input: "Foo\nfoo\n",
+			opts:  []runetrie.ReadOption{runetrie.WithCaseInsensitive(), runetrie.WithConflictPolicy(runetrie.ConflictReplace)},
+			want:  []string{"foo"},
+		},
+		{
+			name:     "Conflict",
+			input:    "# conflict\nFoo\n\nfoo\n",
+			opts:     []runetrie.ReadOption{runetrie.WithCaseInsensitive()},
+			wantLine: 4,
+			wantErr:  runetrie.ErrConflictEntry,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			columns = nil
+			tr, err := runetrie.ReadTrie[string](strings.NewReader(tt.input), tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil {
+				var lineErr *runetrie.LineError
+				if !errors.As(err, &lineErr) {
+					t.Fatalf("must be LineError: %v", err)
+				}
+				if lineErr.Line != tt.wantLine {
+					t.Errorf("unexpected line: %d, want %d", lineErr.Line, tt.wantLine)
+				}
+				return
+			}
+
+			got := slices.Collect(tr.All())
+			t.Logf("got: %s", pp.Sprint(got))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected strings.\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantColumns, columns); diff != "" {
+				t.Errorf("unexpected columns.\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadTrie_ColumnsError(t *testing.T) {
+	errColumn := errors.New("column error")
+	_, err := runetrie.ReadTrie[string](strings.NewReader("foo\nbar\tbaz\n"), runetrie.WithColumns(func(key string, columns []string) error {
+		if len(columns) != 0 {
+			return errColumn
+		}
+		return nil
+	}))
+	if !errors.Is(err, errColumn) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := err.Error(), "line 2: column error"; got != want {
+		t.Errorf("unexpected message: %q, want %q", got, want)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("foo\nfoobar\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tr, err := runetrie.LoadFile[string](path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s, ok := tr.LongestMatchPrefixOf("foobarbaz"); s != "foobar" || !ok {
+		t.Errorf("LongestMatchPrefixOf(%q) = %q, %v", "foobarbaz", s, ok)
+	}
+
+	if _, err := runetrie.LoadFile[string](filepath.Join(t.TempDir(), "missing.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
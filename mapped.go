@@ -0,0 +1,61 @@
+package runetrie
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// MappedTrie is a FrozenTrie which answers the queries directly against a memory mapped file.
+// The file is shared with the other processes mapping the same file through the page cache.
+// It must be closed by Close to unmap the file.
+type MappedTrie[T ~string] struct {
+	*FrozenTrie[T]
+	data []byte
+}
+
+// LoadMapped maps the file written by FrozenTrie.WriteTo into the memory, and returns a MappedTrie to query it.
+// It does not deserialize the file, but performs a structural check of it on open.
+// The arrays and the strings are not copied into the heap, so it allocates only a constant number of small objects,
+// such as the file handle and the MappedTrie itself, regardless of the size of the file.
+// On the platforms without mmap(2), it reads the whole file into the heap instead.
+// The checksum is not verified not to read the whole file; use FrozenTrie.UnmarshalBinary to verify it.
+// It returns ErrInvalidFormat if the file is broken.
+func LoadMapped[T ~string](path string) (*MappedTrie[T], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() < frozenHeaderSize+4 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, io.ErrUnexpectedEOF)
+	}
+
+	data, err := mmap(f, int(fi.Size()))
+	if err != nil {
+		return nil, err
+	}
+	ft, err := decodeFrozenTrie[T](data, true)
+	if err != nil {
+		_ = munmap(data)
+		return nil, err
+	}
+	return &MappedTrie[T]{FrozenTrie: ft, data: data}, nil
+}
+
+// Close unmaps the file.
+// The MappedTrie must not be used after Close, but the strings returned from it remain valid.
+func (mt *MappedTrie[T]) Close() error {
+	if mt.data == nil {
+		return nil
+	}
+	data := mt.data
+	mt.data = nil
+	mt.FrozenTrie = nil
+	return munmap(data)
+}
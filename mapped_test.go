@@ -0,0 +1,182 @@
+package runetrie_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/karupanerura/runetrie"
+)
+
+func TestLoadMapped(t *testing.T) {
+	tests := []struct {
+		name    string
+		trie    *runetrie.Trie[string]
+		targets []string
+	}{
+		{
+			name:    "Empty",
+			trie:    runetrie.NewTrie[string](),
+			targets: []string{"", "foo"},
+		},
+		{
+			name:    "CaseSensitive",
+			trie:    runetrie.NewTrie("", "A", "AA", "AAA", "ABCA", "東京都"),
+			targets: []string{"", "A", "AAC", "ABCABC", "abca", "東京都庁"},
+		},
+		{
+			name:    "CaseInsensitive",
+			trie:    runetrie.Must(runetrie.NewCaseInsensitiveTrie("Foo", "BAR", "application/JSON")),
+			targets: []string{"foo", "FOOBAR", "Application/json; charset=utf-8"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "trie.bin")
+			writeFrozenTrie(t, path, tt.trie.Freeze())
+
+			mt, err := runetrie.LoadMapped[string](path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertLookup(t, "MappedTrie", mt, tt.trie, tt.targets)
+
+			var results []string
+			for _, target := range tt.targets {
+				if result, ok := mt.LongestMatchPrefixOf(target); ok {
+					results = append(results, result)
+				}
+			}
+			if err := mt.Close(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, result := range results {
+				if !tt.trie.MatchAny(result) {
+					t.Errorf("%q must remain valid after Close", result)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMapped_Allocs(t *testing.T) {
+	allocs := func(tr *runetrie.Trie[string]) float64 {
+		path := filepath.Join(t.TempDir(), "trie.bin")
+		writeFrozenTrie(t, path, tr.Freeze())
+		return testing.AllocsPerRun(10, func() {
+			mt, err := runetrie.LoadMapped[string](path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_ = mt.Close()
+		})
+	}
+
+	small := allocs(runetrie.NewTrie("foo"))
+	large := allocs(runetrie.NewTrie(dictionary[:10000]...))
+	t.Logf("allocs: small=%v large=%v", small, large)
+	if small != large {
+		t.Errorf("LoadMapped must allocate a constant number of objects regardless of the size: %v != %v", small, large)
+	}
+}
+
+func TestLoadMapped_Error(t *testing.T) {
+	data, err := runetrie.NewTrie("foo", "bar").Freeze().MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data func() []byte
+		want error
+	}{
+		{
+			name: "Empty",
+			data: func() []byte { return nil },
+			want: runetrie.ErrInvalidFormat,
+		},
+		{
+			name: "BadMagic",
+			data: func() []byte { return append([]byte("XTRF"), data[4:]...) },
+			want: runetrie.ErrInvalidFormat,
+		},
+		{
+			name: "UnsupportedVersion",
+			data: func() []byte {
+				d := append([]byte(nil), data...)
+				d[4] = 255
+				return d
+			},
+			want: runetrie.ErrUnsupportedVersion,
+		},
+		{
+			name: "Truncated",
+			data: func() []byte { return data[:len(data)-1] },
+			want: runetrie.ErrInvalidFormat,
+		},
+		{
+			name: "BrokenCell",
+			data: func() []byte {
+				d := append([]byte(nil), data...)
+				cells := binary.LittleEndian.Uint64(d[24:])
+				// points the parent of the first cell out of the array
+				binary.LittleEndian.PutUint32(d[48+4*cells:], uint32(cells))
+				return d
+			},
+			want: runetrie.ErrInvalidFormat,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "trie.bin")
+			if err := os.WriteFile(path, tt.data(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := runetrie.LoadMapped[string](path); !errors.Is(err, tt.want) {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			var ft runetrie.FrozenTrie[string]
+			if err := ft.UnmarshalBinary(tt.data()); !errors.Is(err, runetrie.ErrInvalidFormat) && !errors.Is(err, tt.want) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFrozenTrie_UnmarshalBinary(t *testing.T) {
+	tr := runetrie.Must(runetrie.NewCaseInsensitiveTrie("", "Foo", "BAR", "東京都"))
+	data, err := tr.Freeze().MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ft runetrie.FrozenTrie[string]
+	if err := ft.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertLookup(t, "FrozenTrie", &ft, tr, []string{"", "foo", "BARBAZ", "東京都庁"})
+
+	data[len(data)-5] ^= 1
+	if err := ft.UnmarshalBinary(data); !errors.Is(err, runetrie.ErrInvalidFormat) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func writeFrozenTrie(t *testing.T, path string, ft *runetrie.FrozenTrie[string]) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := ft.WriteTo(f); err != nil {
+		t.Fatal(err)
+	}
+}
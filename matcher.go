@@ -0,0 +1,174 @@
+package runetrie
+
+import (
+	"iter"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Match is an occurrence of a string in the Trie found in a text.
+type Match[T ~string] struct {
+	// Key is the matched string in the Trie.
+	Key T
+	// Start and End are the byte offsets of the occurrence in the text.
+	// In case insensitive mode, End-Start may differ from the length of Key.
+	Start, End int
+}
+
+// Matcher is an Aho-Corasick automaton built on top of a Trie.
+// It finds all the occurrences of all the strings in the Trie in a single left-to-right pass over a text.
+// It is a snapshot of the Trie at the time of Compile, so it is not affected by later changes to the Trie.
+// It is safe for concurrent use.
+type Matcher[T ~string] struct {
+	states   []matcherState[T]
+	maxDepth int32
+}
+
+type matcherState[T ~string] struct {
+	next  map[rune]int32
+	fail  int32 // the state of the longest proper suffix which is also a prefix of some strings
+	out   int32 // the nearest terminal state on the failure chain, or -1 if there is none
+	depth int32 // the number of runes from the root
+	s     T
+	e     bool
+}
+
+// Compile builds a Matcher from the strings in the Trie.
+// In case insensitive mode, the Matcher also matches in a case insensitive manner.
+// It panics if the Trie is normalized by WithNormalization, WithRuneMapper, WithWidthInsensitive or WithKanaInsensitive.
+func (t *Trie[T]) Compile() *Matcher[T] {
+	t.mustNotBeNormalized("Compile")
+	// a state is a position in the Trie, which may be in the middle of the label of a node
+	type position struct {
+		node *Trie[T]
+		off  int
+	}
+	index := map[position]int32{{t, 0}: 0}
+	positions := []position{{t, 0}}
+	states := []matcherState[T]{{out: -1, s: t.s, e: t.e}}
+	parents := []int32{-1}
+	runes := []rune{0}
+	add := func(u int32, c rune, pos position) int32 {
+		if v, ok := index[pos]; ok {
+			return v
+		}
+		v := int32(len(positions))
+		index[pos] = v
+		positions = append(positions, pos)
+		state := matcherState[T]{depth: states[u].depth + 1}
+		if pos.off == len(pos.node.p) {
+			state.s, state.e = pos.node.s, pos.node.e
+		}
+		states = append(states, state)
+		parents = append(parents, u)
+		runes = append(runes, c)
+		return v
+	}
+
+	// number the states in BFS order, so that every failure link points to a state numbered before
+	for u := int32(0); int(u) < len(positions); u++ {
+		pos := positions[u]
+		if pos.off < len(pos.node.p) {
+			c, size := utf8.DecodeRuneInString(pos.node.p[pos.off:])
+			v := add(u, c, position{pos.node, pos.off + size})
+			next := map[rune]int32{c: v}
+			if t.i {
+				for r := unicode.SimpleFold(c); r != c; r = unicode.SimpleFold(r) {
+					next[r] = v
+				}
+			}
+			states[u].next = next
+			continue
+		}
+		if len(pos.node.m) == 0 {
+			continue
+		}
+
+		next := make(map[rune]int32, len(pos.node.m))
+		for c, leaf := range pos.node.m {
+			next[c] = add(u, c, position{leaf, 0})
+		}
+		states[u].next = next
+	}
+
+	for v := 1; v < len(states); v++ {
+		fail := int32(0)
+		if u := parents[v]; u != 0 {
+			for f := states[u].fail; ; f = states[f].fail {
+				if w, ok := states[f].next[runes[v]]; ok {
+					fail = w
+					break
+				}
+				if f == 0 {
+					break
+				}
+			}
+		}
+		states[v].fail = fail
+		if states[fail].e {
+			states[v].out = fail
+		} else {
+			states[v].out = states[fail].out
+		}
+	}
+
+	return &Matcher[T]{states: states, maxDepth: states[len(states)-1].depth}
+}
+
+// Matches returns an iterator over all the occurrences of all the strings in the Trie found in the given text,
+// including the overlapping ones.
+// The occurrences are ordered by their end offsets, and the ones sharing the same end offset are ordered from the longest.
+func (m *Matcher[T]) Matches(text T) iter.Seq[Match[T]] {
+	return func(yield func(Match[T]) bool) {
+		if m.states[0].e && !yield(Match[T]{Key: m.states[0].s}) {
+			return
+		}
+
+		state := int32(0)
+		for i := 0; i < len(text); {
+			c, size := rune(text[i]), 1
+			if c >= utf8.RuneSelf {
+				c, size = utf8.DecodeRuneInString(string(text[i:]))
+			}
+			i += size
+
+			state = m.step(state, c)
+			for o := m.output(state); o >= 0; o = m.states[o].out {
+				if !yield(Match[T]{Key: m.states[o].s, Start: m.start(text, i, m.states[o].depth), End: i}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// step returns the next state of the given state with the given rune.
+func (m *Matcher[T]) step(state int32, c rune) int32 {
+	for {
+		if next, ok := m.states[state].next[c]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = m.states[state].fail
+	}
+}
+
+// output returns the longest terminal state on the failure chain of the given state including itself, or -1.
+func (m *Matcher[T]) output(state int32) int32 {
+	if m.states[state].e {
+		return state
+	}
+	return m.states[state].out
+}
+
+// start returns the byte offset of the rune which is the given number of runes before the end in the text.
+func (m *Matcher[T]) start(text T, end int, depth int32) int {
+	start := end
+	for ; depth > 0; depth-- {
+		_, size := utf8.DecodeLastRuneInString(string(text[:start]))
+		start -= size
+	}
+	return start
+}
@@ -0,0 +1,167 @@
+package runetrie_test
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k0kubun/pp"
+	"github.com/karupanerura/runetrie"
+)
+
+func Test_Matcher_Matches(t *testing.T) {
+	type M = runetrie.Match[string]
+	tests := []struct {
+		name string
+		set  []string
+		text string
+		want []M
+	}{
+		{
+			name: "Empty",
+			set:  []string{},
+			text: "foo",
+			want: nil,
+		},
+		{
+			name: "Classic",
+			set:  []string{"he", "she", "his", "hers"},
+			text: "ushers",
+			want: []M{{"she", 1, 4}, {"he", 2, 4}, {"hers", 2, 6}},
+		},
+		{
+			name: "Overlapping",
+			set:  []string{"a", "aa", "aaa"},
+			text: "aaaa",
+			want: []M{
+				{"a", 0, 1},
+				{"aa", 0, 2}, {"a", 1, 2},
+				{"aaa", 0, 3}, {"aa", 1, 3}, {"a", 2, 3},
+				{"aaa", 1, 4}, {"aa", 2, 4}, {"a", 3, 4},
+			},
+		},
+		{
+			name: "MultiByte",
+			set:  []string{"東京", "京都", "都"},
+			text: "東京都",
+			want: []M{{"東京", 0, 6}, {"京都", 3, 9}, {"都", 6, 9}},
+		},
+		{
+			name: "EmptyEntry",
+			set:  []string{"", "b"},
+			text: "ab",
+			want: []M{{"", 0, 0}, {"", 1, 1}, {"b", 1, 2}, {"", 2, 2}},
+		},
+		{
+			name: "CaseSensitive",
+			set:  []string{"foo"},
+			text: "FOO",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrie(tt.set...)
+			got := slices.Collect(tr.Compile().Matches(tt.text))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Matcher.Matches() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tr))
+			}
+		})
+	}
+}
+
+func Test_CaseInsensitiveMatcher_Matches(t *testing.T) {
+	type M = runetrie.Match[string]
+	tests := []struct {
+		name string
+		set  []string
+		text string
+		want []M
+	}{
+		{
+			name: "Classic",
+			set:  []string{"He", "SHE", "his", "hers"},
+			text: "UsHeRs",
+			want: []M{{"SHE", 1, 4}, {"He", 2, 4}, {"hers", 2, 6}},
+		},
+		{
+			name: "MultiByte",
+			set:  []string{"ÀB", "b"},
+			text: "xàbB",
+			want: []M{{"ÀB", 1, 4}, {"b", 3, 4}, {"b", 4, 5}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.Must(runetrie.NewCaseInsensitiveTrie(tt.set...))
+			got := slices.Collect(tr.Compile().Matches(tt.text))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Matcher.Matches() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tr))
+			}
+		})
+	}
+}
+
+func TestMatcher_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	alphabet := []rune("abAB√")
+	for i := 0; i < 200; i++ {
+		set := make([]string, rnd.Intn(8))
+		for j := range set {
+			set[j] = randomString(rnd, alphabet, 4)
+		}
+		text := randomString(rnd, alphabet, 32)
+
+		for _, tr := range []*runetrie.Trie[string]{runetrie.NewTrie(set...), caseInsensitiveTrieOf(set...)} {
+			// the brute force oracle: all the prefixes at every rune offset
+			var want []runetrie.Match[string]
+			for start := 0; start <= len(text); {
+				for end, key := range tr.AllPrefixesOf(text[start:]) {
+					want = append(want, runetrie.Match[string]{Key: key, Start: start, End: start + end})
+				}
+				if start == len(text) {
+					break
+				}
+				_, size := utf8.DecodeRuneInString(text[start:])
+				start += size
+			}
+			slices.SortStableFunc(want, func(a, b runetrie.Match[string]) int {
+				if a.End != b.End {
+					return a.End - b.End
+				}
+				return a.Start - b.Start
+			})
+
+			got := slices.Collect(tr.Compile().Matches(text))
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("Matcher.Matches(%q) with %q mismatch.\n%s", text, set, diff)
+			}
+		}
+	}
+}
+
+// randomString returns a string of the runes in the alphabet, whose length is up to n runes.
+func randomString(rnd *rand.Rand, alphabet []rune, n int) string {
+	var sb strings.Builder
+	for i := rnd.Intn(n + 1); i > 0; i-- {
+		sb.WriteRune(alphabet[rnd.Intn(len(alphabet))])
+	}
+	return sb.String()
+}
+
+// caseInsensitiveTrieOf builds a case insensitive Trie skipping the conflicting strings.
+func caseInsensitiveTrieOf(ss ...string) *runetrie.Trie[string] {
+	tr := runetrie.Must(runetrie.NewCaseInsensitiveTrie[string]())
+	for _, s := range ss {
+		_ = tr.Add(s)
+	}
+	return tr
+}
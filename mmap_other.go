@@ -0,0 +1,21 @@
+//go:build !unix
+
+package runetrie
+
+import (
+	"io"
+	"os"
+)
+
+// mmap reads the whole file instead on the platforms without mmap(2).
+func mmap(f *os.File, size int) ([]byte, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func munmap([]byte) error {
+	return nil
+}
@@ -0,0 +1,267 @@
+package runetrie
+
+import (
+	"iter"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+)
+
+// RuneMapper maps a rune to the representative rune of its equivalence class,
+// such as the one without accents or the one of the same digit in other scripts.
+// If MapRune returns a negative value, the rune is dropped like strings.Map.
+type RuneMapper interface {
+	MapRune(r rune) rune
+}
+
+// RuneMapperFunc is an adapter to use an ordinary function as a RuneMapper.
+type RuneMapperFunc func(r rune) rune
+
+// MapRune calls f(r).
+func (f RuneMapperFunc) MapRune(r rune) rune {
+	return f(r)
+}
+
+// normalizer transforms the strings on Add and the input on lookups into the same normal form.
+// The input is transformed incrementally by segments, so the matches are reported at the segment boundaries
+// with the offsets in the original input.
+type normalizer struct {
+	form    *norm.Form // nil if the strings are not normalized by a form
+	width   bool       // width insensitive
+	kana    bool       // hiragana and katakana insensitive
+	mappers []RuneMapper
+}
+
+// key returns the normalized form of the string to be stored in the Trie.
+// It is the concatenation of the segments, so it is consistent with the lookups.
+func (n *normalizer) key(s string) string {
+	var b strings.Builder
+	for _, segment := range n.segments(s) {
+		b.Write(segment)
+	}
+	return b.String()
+}
+
+func (n *normalizer) mapping() bool {
+	return n.width || n.kana || len(n.mappers) != 0
+}
+
+func (n *normalizer) mapRune(c rune) rune {
+	if n.width {
+		if f := width.LookupRune(c).Folded(); f != 0 {
+			c = f
+		}
+	}
+	if n.kana {
+		c = hiragana(c)
+	}
+	for _, m := range n.mappers {
+		if c = m.MapRune(c); c < 0 {
+			break
+		}
+	}
+	return c
+}
+
+// segments returns an iterator over the normalized segments of the given input,
+// together with the byte offset in s where each segment ends.
+// Without a normalization form, each rune is a segment,
+// except that the voiced sound marks are in the same segment as the preceding rune in width insensitive mode.
+func (n *normalizer) segments(s string) iter.Seq2[int, []byte] {
+	return func(yield func(int, []byte) bool) {
+		if n.width {
+			s = widen(s)
+		}
+
+		var buf []byte
+		emit := func(end int, segment []byte) bool {
+			if !n.mapping() {
+				return yield(end, segment)
+			}
+
+			buf = buf[:0]
+			for i := 0; i < len(segment); {
+				c, size := utf8.DecodeRune(segment[i:])
+				i += size
+				if c = n.mapRune(c); c >= 0 {
+					buf = utf8.AppendRune(buf, c)
+				}
+			}
+			return yield(end, buf)
+		}
+
+		if n.form == nil {
+			for i := 0; i < len(s); {
+				c, size := utf8.DecodeRuneInString(s[i:])
+				if n.width && i+size < len(s) && isVoicedMark(s[i+size:]) {
+					// composes the half-width katakana with the voiced sound marks, such as "ｶﾞ" into "ガ"
+					start := i
+					i += size
+					for i < len(s) && isVoicedMark(s[i:]) {
+						i += len(voicedMark)
+					}
+					if !emit(i, []byte(norm.NFC.String(s[start:i]))) {
+						return
+					}
+					continue
+				}
+				i += size
+
+				buf = buf[:0]
+				if c = n.mapRune(c); c >= 0 {
+					buf = utf8.AppendRune(buf, c)
+				}
+				if !yield(i, buf) {
+					return
+				}
+			}
+			return
+		}
+
+		var it norm.Iter
+		it.InitString(*n.form, s)
+		for !it.Done() {
+			segment := it.Next()
+			if !emit(it.Pos(), segment) {
+				return
+			}
+		}
+	}
+}
+
+// voicedMark is the combining voiced sound mark U+3099, and the semi-voiced one U+309A differs only in the last byte in UTF-8.
+const voicedMark = "\u3099"
+
+func isVoicedMark(s string) bool {
+	return len(s) >= len(voicedMark) && s[:2] == voicedMark[:2] && (s[2] == voicedMark[2] || s[2] == voicedMark[2]+1)
+}
+
+// widen replaces the half-width runes with their wide forms of the same byte length, such as "ｶ" with "カ" and "ﾞ" with U+3099,
+// so the offsets in the result are the same as in s.
+// It copies s only if there is such a rune.
+func widen(s string) string {
+	var b []byte
+	for i := 0; i < len(s); {
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if f := width.LookupRune(c).Folded(); f != 0 && utf8.RuneLen(f) == size {
+			if b == nil {
+				b = []byte(s)
+			}
+			utf8.EncodeRune(b[i:], f)
+		}
+		i += size
+	}
+	if b == nil {
+		return s
+	}
+	return string(b)
+}
+
+// hiragana returns the hiragana of the katakana c, or c itself if there is none.
+func hiragana(c rune) rune {
+	if 'ァ' <= c && c <= 'ヶ' || 'ヽ' <= c && c <= 'ヾ' {
+		return c - ('ア' - 'あ')
+	}
+	return c
+}
+
+// WithNormalization makes the Trie insensitive to the Unicode normalization by the given form,
+// such as norm.NFC, norm.NFD, norm.NFKC and norm.NFKD.
+// The strings are normalized on Add and the input is normalized incrementally during the lookups,
+// while the offsets of the matches are in terms of the original input.
+// A match is only found at a boundary of the normalization, so "e" is not a prefix of "é".
+// Cursor, Compile, Freeze and Minimize walk the raw runes, so they panic on a normalized Trie.
+// The normalization is not serialized, so MarshalBinary and WriteTo return ErrNormalized.
+func WithNormalization(form norm.Form) Option {
+	return func(o *options) {
+		o.normalizer().form = &form
+	}
+}
+
+// WithRuneMapper makes the Trie insensitive to the differences of the runes in the same equivalence class of the given RuneMapper.
+// The strings are mapped on Add and the input is mapped during the lookups like WithNormalization,
+// and the strings mapped to the same one conflict with each other as in case insensitive mode.
+// If it is given multiple times, the RuneMappers are applied in order, after the normalization form if any.
+// For example, WithNormalization(norm.NFD) and a RuneMapper which drops the nonspacing marks (unicode.Mn) strip the accents.
+func WithRuneMapper(m RuneMapper) Option {
+	return func(o *options) {
+		n := o.normalizer()
+		n.mappers = append(n.mappers, m)
+	}
+}
+
+// WithWidthInsensitive makes the Trie insensitive to the differences of the full-width and half-width forms,
+// such as "Ａ" and "A", and "ｱ" and "ア".
+// The half-width katakana followed by the half-width voiced sound mark is the same as the voiced katakana, so "ｶﾞ" is "ガ".
+// As with WithNormalization, the offsets of the matches are in terms of the original input.
+func WithWidthInsensitive() Option {
+	return func(o *options) {
+		o.normalizer().width = true
+	}
+}
+
+// WithKanaInsensitive makes the Trie insensitive to the differences of hiragana and katakana, such as "あ" and "ア".
+// Use it with WithWidthInsensitive to match the half-width katakana too.
+func WithKanaInsensitive() Option {
+	return func(o *options) {
+		o.normalizer().kana = true
+	}
+}
+
+// mustNotBeNormalized panics if the Trie is normalized,
+// for the operations which walk the raw runes and would silently miss the normalized strings.
+func (t *Trie[T]) mustNotBeNormalized(op string) {
+	if t.n != nil {
+		panic("runetrie: " + op + " is not supported on a normalized Trie")
+	}
+}
+
+// normalized walks the Trie along the normalized input, and calls fn with the terminal nodes
+// reached at the boundaries of the segments, from the shortest to the longest,
+// together with the byte offset in s where each match ends.
+// If all the rest segments are dropped by the RuneMapper, the last match ends at the end of s.
+// It stops when fn returns false.
+func (t *Trie[T]) normalized(s string, fn func(end int, leaf *Trie[T]) bool) {
+	// the match is reported on the next segment which is not dropped, or at the end of s
+	var match *Trie[T]
+	if t.e {
+		match = t
+	}
+	matchEnd := 0
+
+	tree, off, last := t, 0, 0
+	for end, segment := range t.n.segments(s) {
+		if len(segment) == 0 {
+			// the segment is dropped by the RuneMapper
+			continue
+		}
+		if match != nil {
+			if !fn(matchEnd, match) {
+				return
+			}
+			match = nil
+		}
+
+		for i := 0; i < len(segment); {
+			c, size := utf8.DecodeRune(segment[i:])
+			i += size
+
+			if tree, off = tree.next(c, off, t.i); tree == nil {
+				return
+			}
+		}
+		// a decomposition may span several segments ending at the same offset, and only the last one is a boundary
+		if end == last {
+			continue
+		}
+		last = end
+		if tree.e && off == len(tree.p) {
+			match, matchEnd = tree, end
+		}
+	}
+	if match != nil {
+		fn(len(s), match)
+	}
+}
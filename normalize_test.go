@@ -0,0 +1,525 @@
+package runetrie_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"unicode"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k0kubun/pp"
+	"github.com/karupanerura/runetrie"
+	"golang.org/x/text/unicode/norm"
+)
+
+func Test_NormalizedTrie(t *testing.T) {
+	type prefix struct {
+		End int
+		Key string
+	}
+	tests := []struct {
+		name         string
+		form         norm.Form
+		set          []string
+		target       string
+		wantAny      bool
+		wantPrefixes []prefix
+	}{
+		{
+			name:         "NFCKeyNFDInput",
+			form:         norm.NFC,
+			set:          []string{"caf\u00e9", "caf"},
+			target:       "cafe\u0301",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 3, Key: "caf"}, {End: 6, Key: "caf\u00e9"}},
+		},
+		{
+			name:         "NFDKeyNFCInput",
+			form:         norm.NFD,
+			set:          []string{"cafe\u0301"},
+			target:       "caf\u00e9s",
+			wantAny:      false,
+			wantPrefixes: []prefix{{End: 5, Key: "cafe\u0301"}},
+		},
+		{
+			name:         "NotPrefixInsideSegment",
+			form:         norm.NFD,
+			set:          []string{"cafe"},
+			target:       "caf\u00e9",
+			wantAny:      false,
+			wantPrefixes: nil,
+		},
+		{
+			name:         "NFKCHalfWidthKatakana",
+			form:         norm.NFKC,
+			set:          []string{"ガ", "ガギ"},
+			target:       "ｶﾞｷﾞ",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 6, Key: "ガ"}, {End: 12, Key: "ガギ"}},
+		},
+		{
+			name:         "NFKCLigature",
+			form:         norm.NFKC,
+			set:          []string{"f", "fi"},
+			target:       "ﬁx",
+			wantAny:      false,
+			wantPrefixes: []prefix{{End: 3, Key: "fi"}},
+		},
+		{
+			name:         "EmptyEntry",
+			form:         norm.NFC,
+			set:          []string{"", "e\u0301"},
+			target:       "\u00e9",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 0, Key: ""}, {End: 2, Key: "e\u0301"}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrieWithOptions[string](runetrie.WithNormalization(tt.form))
+			if err := tr.Add(tt.set...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := tr.MatchAny(tt.target); got != tt.wantAny {
+				t.Errorf("Trie.MatchAny() = %v, want %v", got, tt.wantAny)
+			}
+
+			var got []prefix
+			for end, key := range tr.AllPrefixesOf(tt.target) {
+				got = append(got, prefix{End: end, Key: key})
+			}
+			t.Logf("got: %s", pp.Sprint(got))
+			if diff := cmp.Diff(tt.wantPrefixes, got); diff != "" {
+				t.Errorf("Trie.AllPrefixesOf() mismatch.\n%s", diff)
+			}
+
+			shortest, ok := tr.MatchPrefixOf(tt.target)
+			if ok != (len(tt.wantPrefixes) != 0) || ok && shortest != tt.wantPrefixes[0].Key {
+				t.Errorf("Trie.MatchPrefixOf() = %q, %v", shortest, ok)
+			}
+			longest, ok := tr.LongestMatchPrefixOf(tt.target)
+			if ok != (len(tt.wantPrefixes) != 0) || ok && longest != tt.wantPrefixes[len(tt.wantPrefixes)-1].Key {
+				t.Errorf("Trie.LongestMatchPrefixOf() = %q, %v", longest, ok)
+			}
+		})
+	}
+}
+
+func TestNormalizedTrie_Conflict(t *testing.T) {
+	tr := runetrie.NewTrieWithOptions[string](runetrie.WithNormalization(norm.NFC))
+	err := tr.Add("caf\u00e9", "cafe\u0301")
+	if !errors.Is(err, runetrie.ErrConflictEntry) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := tr.AddAtomic("na\u00efve", "nai\u0308ve"); !errors.Is(err, runetrie.ErrConflictEntry) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"caf\u00e9"}, slices.Collect(tr.All())); diff != "" {
+		t.Errorf("unexpected strings.\n%s", diff)
+	}
+}
+
+func TestNormalizedTrie_Remove(t *testing.T) {
+	tr := runetrie.NewTrieWithOptions[string](runetrie.WithNormalization(norm.NFC))
+	if err := tr.Add("caf\u00e9", "caf\u00e9s"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"caf\u00e9", "caf\u00e9s"}, slices.Collect(tr.KeysWithPrefix("cafe\u0301"))); diff != "" {
+		t.Errorf("Trie.KeysWithPrefix() mismatch.\n%s", diff)
+	}
+	if removed := tr.Remove("cafe\u0301s"); removed != 1 {
+		t.Errorf("Trie.Remove() = %d, want 1", removed)
+	}
+	if diff := cmp.Diff([]string{"caf\u00e9"}, slices.Collect(tr.All())); diff != "" {
+		t.Errorf("unexpected strings.\n%s", diff)
+	}
+}
+
+func TestNormalizedTrie_FindAllIndex(t *testing.T) {
+	tr := runetrie.NewTrieWithOptions[string](runetrie.WithNormalization(norm.NFC))
+	if err := tr.Add("\u00e9t\u00e9"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := "un e\u0301te\u0301 \u00e9t\u00e9"
+	got := tr.FindAllIndex(text, -1)
+	want := [][2]int{{3, 10}, {11, 16}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Trie.FindAllIndex() mismatch.\n%s", diff)
+	}
+}
+
+func TestNormalizedTrie_Unsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(tr *runetrie.Trie[string])
+	}{
+		{name: "Cursor", fn: func(tr *runetrie.Trie[string]) { tr.Cursor() }},
+		{name: "Compile", fn: func(tr *runetrie.Trie[string]) { tr.Compile() }},
+		{name: "Freeze", fn: func(tr *runetrie.Trie[string]) { tr.Freeze() }},
+		{name: "Minimize", fn: func(tr *runetrie.Trie[string]) { tr.Minimize() }},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrieWithOptions[string](runetrie.WithWidthInsensitive(), runetrie.WithKanaInsensitive())
+			if err := tr.Add("ガ"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Trie.%s() must panic on a normalized Trie", tt.name)
+				}
+			}()
+			tt.fn(tr)
+		})
+	}
+}
+
+// stripAccents drops the nonspacing marks, which strips the accents with norm.NFD.
+var stripAccents = runetrie.RuneMapperFunc(func(r rune) rune {
+	if unicode.Is(unicode.Mn, r) {
+		return -1
+	}
+	return r
+})
+
+// unifyDigits maps the full-width and the Arabic-Indic digits to the ASCII ones.
+var unifyDigits = runetrie.RuneMapperFunc(func(r rune) rune {
+	switch {
+	case '０' <= r && r <= '９':
+		return '0' + r - '０'
+	case '٠' <= r && r <= '٩':
+		return '0' + r - '٠'
+	}
+	return r
+})
+
+// skeleton maps the Cyrillic homoglyphs to the Latin letters.
+var skeleton = runetrie.RuneMapperFunc(func(r rune) rune {
+	switch r {
+	case 'а':
+		return 'a'
+	case 'е':
+		return 'e'
+	case 'о':
+		return 'o'
+	case 'р':
+		return 'p'
+	}
+	return r
+})
+
+// dropHyphens drops the hyphens.
+var dropHyphens = runetrie.RuneMapperFunc(func(r rune) rune {
+	if r == '-' {
+		return -1
+	}
+	return r
+})
+
+func Test_RuneMapperTrie(t *testing.T) {
+	type prefix struct {
+		End int
+		Key string
+	}
+	tests := []struct {
+		name         string
+		opts         []runetrie.Option
+		set          []string
+		target       string
+		wantAny      bool
+		wantPrefixes []prefix
+	}{
+		{
+			name:         "StripAccents",
+			opts:         []runetrie.Option{runetrie.WithNormalization(norm.NFD), runetrie.WithRuneMapper(stripAccents)},
+			set:          []string{"café", "re"},
+			target:       "résumé",
+			wantAny:      false,
+			wantPrefixes: []prefix{{End: 3, Key: "re"}},
+		},
+		{
+			name:         "StripAccentsCaseInsensitive",
+			opts:         []runetrie.Option{runetrie.WithCaseInsensitive(), runetrie.WithNormalization(norm.NFD), runetrie.WithRuneMapper(stripAccents)},
+			set:          []string{"café"},
+			target:       "CAFE",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 4, Key: "café"}},
+		},
+		{
+			name:         "UnifyDigits",
+			opts:         []runetrie.Option{runetrie.WithRuneMapper(unifyDigits)},
+			set:          []string{"2024", "20"},
+			target:       "٢٠٢٤",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 4, Key: "20"}, {End: 8, Key: "2024"}},
+		},
+		{
+			name:         "UnifyDigitsFullWidth",
+			opts:         []runetrie.Option{runetrie.WithRuneMapper(unifyDigits)},
+			set:          []string{"1"},
+			target:       "１",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 3, Key: "1"}},
+		},
+		{
+			name:         "Skeleton",
+			opts:         []runetrie.Option{runetrie.WithRuneMapper(skeleton)},
+			set:          []string{"paypal"},
+			target:       "pаypаl.com",
+			wantAny:      false,
+			wantPrefixes: []prefix{{End: 8, Key: "paypal"}},
+		},
+		{
+			name:         "ComposedMappers",
+			opts:         []runetrie.Option{runetrie.WithRuneMapper(unifyDigits), runetrie.WithRuneMapper(skeleton)},
+			set:          []string{"po1"},
+			target:       "ро１",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 7, Key: "po1"}},
+		},
+		{
+			name:         "DropRunes",
+			opts:         []runetrie.Option{runetrie.WithRuneMapper(dropHyphens)},
+			set:          []string{"email", "e"},
+			target:       "e-mail",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 1, Key: "e"}, {End: 6, Key: "email"}},
+		},
+		{
+			name:         "DropTrailingRunes",
+			opts:         []runetrie.Option{runetrie.WithRuneMapper(dropHyphens)},
+			set:          []string{"e", "em"},
+			target:       "e-m--",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 1, Key: "e"}, {End: 5, Key: "em"}},
+		},
+		{
+			name:         "DropAllRunes",
+			opts:         []runetrie.Option{runetrie.WithRuneMapper(dropHyphens)},
+			set:          []string{""},
+			target:       "--",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 2, Key: ""}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrieWithOptions[string](tt.opts...)
+			if err := tr.Add(tt.set...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := tr.MatchAny(tt.target); got != tt.wantAny {
+				t.Errorf("Trie.MatchAny() = %v, want %v", got, tt.wantAny)
+			}
+
+			var got []prefix
+			for end, key := range tr.AllPrefixesOf(tt.target) {
+				got = append(got, prefix{End: end, Key: key})
+			}
+			t.Logf("got: %s", pp.Sprint(got))
+			if diff := cmp.Diff(tt.wantPrefixes, got); diff != "" {
+				t.Errorf("Trie.AllPrefixesOf() mismatch.\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRuneMapperTrie_Conflict(t *testing.T) {
+	tr := runetrie.NewTrieWithOptions[string](runetrie.WithNormalization(norm.NFD), runetrie.WithRuneMapper(stripAccents))
+	err := tr.Add("résumé", "resume", "résume")
+	want := []runetrie.ConflictError[string]{{Existing: "résumé", New: "resume"}, {Existing: "résumé", New: "résume"}}
+	if diff := cmp.Diff(want, conflictsOf(t, err)); diff != "" {
+		t.Errorf("unexpected conflicts.\n%s", diff)
+	}
+}
+
+func TestRuneMapperTrie_TrailingDroppedRune(t *testing.T) {
+	tr := runetrie.NewTrieWithOptions[string](runetrie.WithRuneMapper(dropHyphens))
+	if err := tr.Add("a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tr.MatchAny("a-") {
+		t.Errorf("Trie.MatchAny(%q) must match", "a-")
+	}
+	if err := tr.Add("a-"); !errors.Is(err, runetrie.ErrConflictEntry) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := tr.FindAllIndex("a- b a--", -1); !cmp.Equal(got, [][2]int{{0, 1}, {5, 8}}) {
+		t.Errorf("Trie.FindAllIndex() = %v", got)
+	}
+	if removed := tr.Remove("a-"); removed != 1 {
+		t.Errorf("Trie.Remove() = %d, want 1", removed)
+	}
+}
+
+func Test_JapaneseTrie(t *testing.T) {
+	type prefix struct {
+		End int
+		Key string
+	}
+	tests := []struct {
+		name         string
+		opts         []runetrie.Option
+		set          []string
+		target       string
+		wantAny      bool
+		wantPrefixes []prefix
+	}{
+		{
+			name:         "HalfWidthKatakana",
+			opts:         []runetrie.Option{runetrie.WithWidthInsensitive()},
+			set:          []string{"アイ"},
+			target:       "ｱｲｳ",
+			wantAny:      false,
+			wantPrefixes: []prefix{{End: 6, Key: "アイ"}},
+		},
+		{
+			name:         "FullWidthKatakanaInput",
+			opts:         []runetrie.Option{runetrie.WithWidthInsensitive()},
+			set:          []string{"ｱｲ"},
+			target:       "アイ",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 6, Key: "ｱｲ"}},
+		},
+		{
+			name:         "FullWidthASCII",
+			opts:         []runetrie.Option{runetrie.WithWidthInsensitive()},
+			set:          []string{"AB", "A"},
+			target:       "ＡＢ",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 3, Key: "A"}, {End: 6, Key: "AB"}},
+		},
+		{
+			name:         "IdeographicSpace",
+			opts:         []runetrie.Option{runetrie.WithWidthInsensitive()},
+			set:          []string{"a b"},
+			target:       "a　b",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 5, Key: "a b"}},
+		},
+		{
+			name:         "VoicedMark",
+			opts:         []runetrie.Option{runetrie.WithWidthInsensitive()},
+			set:          []string{"ガ", "ガギ"},
+			target:       "ｶﾞｷﾞ",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 6, Key: "ガ"}, {End: 12, Key: "ガギ"}},
+		},
+		{
+			name:         "SemiVoicedMark",
+			opts:         []runetrie.Option{runetrie.WithWidthInsensitive()},
+			set:          []string{"ﾊﾟﾝ"},
+			target:       "パン",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 6, Key: "ﾊﾟﾝ"}},
+		},
+		{
+			name:         "NotPrefixBeforeVoicedMark",
+			opts:         []runetrie.Option{runetrie.WithWidthInsensitive()},
+			set:          []string{"カ"},
+			target:       "ｶﾞ",
+			wantAny:      false,
+			wantPrefixes: nil,
+		},
+		{
+			name:         "UncomposableVoicedMark",
+			opts:         []runetrie.Option{runetrie.WithWidthInsensitive()},
+			set:          []string{"\u30a2\u3099"},
+			target:       "ｱﾞ",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 6, Key: "\u30a2\u3099"}},
+		},
+		{
+			name:         "WidthCaseInsensitive",
+			opts:         []runetrie.Option{runetrie.WithWidthInsensitive(), runetrie.WithCaseInsensitive()},
+			set:          []string{"abc"},
+			target:       "ａＢｃ",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 9, Key: "abc"}},
+		},
+		{
+			name:         "Kana",
+			opts:         []runetrie.Option{runetrie.WithKanaInsensitive()},
+			set:          []string{"あい", "ヴ"},
+			target:       "アイ",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 6, Key: "あい"}},
+		},
+		{
+			name:         "KanaWithoutWidth",
+			opts:         []runetrie.Option{runetrie.WithKanaInsensitive()},
+			set:          []string{"あ"},
+			target:       "ｱ",
+			wantAny:      false,
+			wantPrefixes: nil,
+		},
+		{
+			name:         "WidthAndKana",
+			opts:         []runetrie.Option{runetrie.WithWidthInsensitive(), runetrie.WithKanaInsensitive()},
+			set:          []string{"がっこう", "が"},
+			target:       "ｶﾞｯｺｳ",
+			wantAny:      true,
+			wantPrefixes: []prefix{{End: 6, Key: "が"}, {End: 15, Key: "がっこう"}},
+		},
+		{
+			name:         "WidthNFD",
+			opts:         []runetrie.Option{runetrie.WithWidthInsensitive(), runetrie.WithNormalization(norm.NFD)},
+			set:          []string{"ガ"},
+			target:       "ｶﾞｷ",
+			wantAny:      false,
+			wantPrefixes: []prefix{{End: 6, Key: "ガ"}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrieWithOptions[string](tt.opts...)
+			if err := tr.Add(tt.set...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := tr.MatchAny(tt.target); got != tt.wantAny {
+				t.Errorf("Trie.MatchAny() = %v, want %v", got, tt.wantAny)
+			}
+
+			var got []prefix
+			for end, key := range tr.AllPrefixesOf(tt.target) {
+				got = append(got, prefix{End: end, Key: key})
+			}
+			t.Logf("got: %s", pp.Sprint(got))
+			if diff := cmp.Diff(tt.wantPrefixes, got); diff != "" {
+				t.Errorf("Trie.AllPrefixesOf() mismatch.\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJapaneseTrie_Conflict(t *testing.T) {
+	tr := runetrie.NewTrieWithOptions[string](runetrie.WithWidthInsensitive(), runetrie.WithKanaInsensitive())
+	err := tr.Add("ガイド", "ｶﾞｲﾄﾞ", "がいど")
+	want := []runetrie.ConflictError[string]{{Existing: "ガイド", New: "ｶﾞｲﾄﾞ"}, {Existing: "ガイド", New: "がいど"}}
+	if diff := cmp.Diff(want, conflictsOf(t, err)); diff != "" {
+		t.Errorf("unexpected conflicts.\n%s", diff)
+	}
+}
+
+func TestJapaneseTrie_FindAllIndex(t *testing.T) {
+	tr := runetrie.NewTrieWithOptions[string](runetrie.WithWidthInsensitive(), runetrie.WithKanaInsensitive())
+	if err := tr.Add("ばなな"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := "ﾊﾞﾅﾅとバナナ"
+	got := tr.FindAllIndex(text, -1)
+	want := [][2]int{{0, 12}, {15, 24}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Trie.FindAllIndex() mismatch.\n%s", diff)
+	}
+}
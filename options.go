@@ -0,0 +1,67 @@
+package runetrie
+
+// Option is an option of NewTrieWithOptions, ReadTrie and LoadFile.
+type Option func(*options)
+
+type options struct {
+	caseInsensitive bool
+	resolve         func(existing, added string) string
+	n               *normalizer
+}
+
+// newTrie creates a new empty Trie with the options.
+func newTrie[T ~string](o *options) *Trie[T] {
+	return &Trie[T]{i: o.caseInsensitive, r: o.resolve, n: o.n}
+}
+
+// normalizer returns the normalizer of the options, creating it if there is none.
+func (o *options) normalizer() *normalizer {
+	if o.n == nil {
+		o.n = &normalizer{}
+	}
+	return o.n
+}
+
+// ConflictPolicy is the policy of the conflicts in case insensitive mode.
+type ConflictPolicy int
+
+const (
+	// ConflictFail returns *ConflictError on a conflict. It is the default.
+	ConflictFail ConflictPolicy = iota
+	// ConflictKeepFirst keeps the existing string on a conflict.
+	ConflictKeepFirst
+	// ConflictReplace replaces the existing string with the new one on a conflict.
+	ConflictReplace
+)
+
+// WithCaseInsensitive makes the Trie case insensitive.
+func WithCaseInsensitive() Option {
+	return func(o *options) {
+		o.caseInsensitive = true
+	}
+}
+
+// WithConflictPolicy sets the policy of the conflicts in case insensitive mode.
+func WithConflictPolicy(p ConflictPolicy) Option {
+	return func(o *options) {
+		switch p {
+		case ConflictKeepFirst:
+			o.resolve = func(existing, _ string) string { return existing }
+		case ConflictReplace:
+			o.resolve = func(_, added string) string { return added }
+		default:
+			o.resolve = nil
+		}
+	}
+}
+
+// WithConflictResolver resolves the conflicts in case insensitive mode by the given function.
+// The function is called with the existing string and the new one, and returns the one to store.
+// If it returns any other string, the existing one is kept and Add returns *ConflictError.
+func WithConflictResolver[T ~string](fn func(existing, added T) T) Option {
+	return func(o *options) {
+		o.resolve = func(existing, added string) string {
+			return string(fn(T(existing), T(added)))
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package runetrie
+
+import (
+	"io"
+	"strings"
+)
+
+// Replacer replaces the occurrences of strings with their replacements, like strings.Replacer.
+// The occurrences are found in the same way as FindAllIndex, so the longest string wins at the same offset.
+// It is safe for concurrent use as long as Set is not called concurrently.
+type Replacer[T ~string] struct {
+	m *TrieMap[T, T]
+}
+
+// NewReplacer creates a new case sensitive Replacer with the given pairs of a string and its replacement.
+func NewReplacer[T ~string](m map[T]T) *Replacer[T] {
+	return &Replacer[T]{m: NewTrieMap(m)}
+}
+
+// NewCaseInsensitiveReplacer creates a new case insensitive Replacer with the given pairs of a string and its replacement.
+// Like NewCaseInsensitiveTrie, it returns ErrConflictEntry if the strings conflict with each other.
+func NewCaseInsensitiveReplacer[T ~string](m map[T]T) (*Replacer[T], error) {
+	tm, err := NewCaseInsensitiveTrieMap(m)
+	if err != nil {
+		return nil, err
+	}
+	return &Replacer[T]{m: tm}, nil
+}
+
+// Set adds a new pair of a string and its replacement, or updates the replacement of the existing string.
+// Only in case insensitive mode, it will check for conflicts.
+// If a conflict is found, it returns *ConflictError and the Replacer is not modified.
+func (r *Replacer[T]) Set(s, replacement T) error {
+	return r.m.Set(s, replacement)
+}
+
+// Replace returns a copy of s with all the replacements performed.
+func (r *Replacer[T]) Replace(s T) T {
+	var sb strings.Builder
+	_, _ = r.WriteString(&sb, s)
+	return T(sb.String())
+}
+
+// WriteString writes s to w with all the replacements performed.
+func (r *Replacer[T]) WriteString(w io.Writer, s T) (n int, err error) {
+	t := r.m.t
+	last := 0
+	t.scan(s, t.longestPrefix, func(start, end int, leaf *Trie[T]) bool {
+		wn, werr := io.WriteString(w, string(s[last:start]))
+		n += wn
+		if werr == nil {
+			wn, werr = io.WriteString(w, string(r.m.v[leaf.s]))
+			n += wn
+		}
+		err = werr
+		last = end
+		return err == nil
+	})
+	if err != nil {
+		return n, err
+	}
+
+	wn, err := io.WriteString(w, string(s[last:]))
+	return n + wn, err
+}
@@ -0,0 +1,134 @@
+package runetrie_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/runetrie"
+)
+
+func Test_Replacer_Replace(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs map[string]string
+		text  string
+		want  string
+	}{
+		{
+			name:  "Empty",
+			pairs: map[string]string{},
+			text:  "foo",
+			want:  "foo",
+		},
+		{
+			name:  "Longest",
+			pairs: map[string]string{"foo": "1", "foobar": "2", "baz": "3"},
+			text:  "foobarbaz foo fooba",
+			want:  "23 1 1ba",
+		},
+		{
+			name:  "Swap",
+			pairs: map[string]string{"a": "b", "b": "a"},
+			text:  "abba",
+			want:  "baab",
+		},
+		{
+			name:  "MultiByte",
+			pairs: map[string]string{"東京都": "Tokyo", "京都": "Kyoto"},
+			text:  "東京都と京都",
+			want:  "TokyoとKyoto",
+		},
+		{
+			name:  "EmptyEntry",
+			pairs: map[string]string{"": "-", "b": "B"},
+			text:  "abc",
+			want:  "-aBc-",
+		},
+		{
+			name:  "CaseSensitive",
+			pairs: map[string]string{"foo": "bar"},
+			text:  "FOO",
+			want:  "FOO",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r := runetrie.NewReplacer(tt.pairs)
+			if diff := cmp.Diff(tt.want, r.Replace(tt.text)); diff != "" {
+				t.Errorf("Replacer.Replace() mismatch.\n%s", diff)
+			}
+
+			var sb strings.Builder
+			n, err := r.WriteString(&sb, tt.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if n != len(tt.want) {
+				t.Errorf("Replacer.WriteString() = %d, want %d", n, len(tt.want))
+			}
+			if diff := cmp.Diff(tt.want, sb.String()); diff != "" {
+				t.Errorf("Replacer.WriteString() mismatch.\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_CaseInsensitiveReplacer_Replace(t *testing.T) {
+	r, err := runetrie.NewCaseInsensitiveReplacer(map[string]string{"foo": "bar", "FooBar": "baz"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("baz bar bar", r.Replace("FOOBAR Foo fOO")); diff != "" {
+		t.Errorf("Replacer.Replace() mismatch.\n%s", diff)
+	}
+
+	if err := r.Set("FOO", "qux"); !errors.Is(err, runetrie.ErrConflictEntry) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := r.Set("foo", "qux"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff("baz qux qux", r.Replace("FOOBAR Foo fOO")); diff != "" {
+		t.Errorf("Replacer.Replace() mismatch.\n%s", diff)
+	}
+}
+
+func TestNewCaseInsensitiveReplacer_Conflict(t *testing.T) {
+	r, err := runetrie.NewCaseInsensitiveReplacer(map[string]string{"foo": "bar", "FOO": "baz"})
+	if r != nil {
+		t.Errorf("must be omit replacer: %+v", r)
+	}
+	if !errors.Is(err, runetrie.ErrConflictEntry) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+type errWriter struct {
+	n int
+}
+
+var errShortWrite = errors.New("short write")
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, errShortWrite
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestReplacer_WriteString_Error(t *testing.T) {
+	r := runetrie.NewReplacer(map[string]string{"foo": "bar"})
+	n, err := r.WriteString(&errWriter{n: 5}, "xxfooxx")
+	if !errors.Is(err, errShortWrite) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if n != 5 {
+		t.Errorf("Replacer.WriteString() = %d, want 5", n)
+	}
+}
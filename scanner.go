@@ -0,0 +1,97 @@
+package runetrie
+
+import (
+	"bufio"
+	"io"
+)
+
+// Scanner finds the occurrences of the strings in the Trie in a stream, like Matcher.Matches.
+// Successive calls to the Scan method step through the occurrences in the same order as Matcher.Matches.
+// The automaton state is carried across the reads, so the occurrences split across the reads are also found.
+// It keeps only the byte sizes of the last runes as many as the longest string in the Trie,
+// so the memory usage is bounded regardless of the length of the stream.
+type Scanner[T ~string] struct {
+	m       *Matcher[T]
+	r       io.RuneReader
+	state   int32
+	pending int32 // the next terminal state to report, or -1
+	offset  int
+	sizes   []int // the ring buffer of the byte sizes of the last runes
+	n       int   // the number of runes read
+	match   Match[T]
+	started bool
+	err     error
+}
+
+// NewScanner returns a new Scanner to read from r.
+// If r does not implement io.RuneReader, it is wrapped by bufio.Reader.
+func (m *Matcher[T]) NewScanner(r io.Reader) *Scanner[T] {
+	rr, ok := r.(io.RuneReader)
+	if !ok {
+		rr = bufio.NewReader(r)
+	}
+	return &Scanner[T]{
+		m:       m,
+		r:       rr,
+		pending: -1,
+		sizes:   make([]int, m.maxDepth),
+	}
+}
+
+// Scan advances the Scanner to the next occurrence, which will then be available through the Match method.
+// It returns false when the scan stops, either by reaching the end of the input or an error.
+// After Scan returns false, the Err method will return any error that occurred during scanning,
+// except that if it was io.EOF, Err will return nil.
+func (s *Scanner[T]) Scan() bool {
+	if !s.started {
+		s.started = true
+		if s.m.states[0].e {
+			s.match = Match[T]{Key: s.m.states[0].s}
+			return true
+		}
+	}
+
+	for s.pending < 0 {
+		if s.err != nil {
+			return false
+		}
+
+		c, size, err := s.r.ReadRune()
+		if err != nil {
+			s.err = err
+			return false
+		}
+		if len(s.sizes) != 0 {
+			s.sizes[s.n%len(s.sizes)] = size
+		}
+		s.n++
+		s.offset += size
+
+		s.state = s.m.step(s.state, c)
+		s.pending = s.m.output(s.state)
+	}
+
+	state := &s.m.states[s.pending]
+	s.pending = state.out
+
+	start := s.offset
+	for i := 1; i <= int(state.depth); i++ {
+		start -= s.sizes[(s.n-i)%len(s.sizes)]
+	}
+	s.match = Match[T]{Key: state.s, Start: start, End: s.offset}
+	return true
+}
+
+// Match returns the most recent occurrence found by a call to Scan.
+// The offsets are in bytes from the beginning of the stream.
+func (s *Scanner[T]) Match() Match[T] {
+	return s.match
+}
+
+// Err returns the first non-EOF error that was encountered by the Scanner.
+func (s *Scanner[T]) Err() error {
+	if s.err == io.EOF {
+		return nil
+	}
+	return s.err
+}
@@ -0,0 +1,97 @@
+package runetrie_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/karupanerura/runetrie"
+)
+
+func Test_Scanner_Scan(t *testing.T) {
+	tests := []struct {
+		name string
+		set  []string
+		text string
+	}{
+		{
+			name: "Empty",
+			set:  []string{},
+			text: "foo",
+		},
+		{
+			name: "Classic",
+			set:  []string{"he", "she", "his", "hers"},
+			text: "ushers",
+		},
+		{
+			name: "Overlapping",
+			set:  []string{"a", "aa", "aaa"},
+			text: "aaaa",
+		},
+		{
+			name: "MultiByte",
+			set:  []string{"東京", "京都", "都"},
+			text: "東京都と京都",
+		},
+		{
+			name: "EmptyEntry",
+			set:  []string{"", "b"},
+			text: "ab",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			m := runetrie.NewTrie(tt.set...).Compile()
+			var want []runetrie.Match[string]
+			for match := range m.Matches(tt.text) {
+				want = append(want, match)
+			}
+
+			// reads one byte at a time so that the multi-byte runes and the strings are split across the reads
+			s := m.NewScanner(iotest.OneByteReader(strings.NewReader(tt.text)))
+			var got []runetrie.Match[string]
+			for s.Scan() {
+				got = append(got, s.Match())
+			}
+			if err := s.Err(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Scanner.Scan() mismatch.\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_CaseInsensitiveScanner_Scan(t *testing.T) {
+	m := runetrie.Must(runetrie.NewCaseInsensitiveTrie("He", "SHE", "his", "hers")).Compile()
+	s := m.NewScanner(strings.NewReader("UsHeRs"))
+	var got []runetrie.Match[string]
+	for s.Scan() {
+		got = append(got, s.Match())
+	}
+	want := []runetrie.Match[string]{{"SHE", 1, 4}, {"He", 2, 4}, {"hers", 2, 6}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Scanner.Scan() mismatch.\n%s", diff)
+	}
+}
+
+func TestScanner_Err(t *testing.T) {
+	errRead := errors.New("read error")
+	m := runetrie.NewTrie("foo").Compile()
+	s := m.NewScanner(io.MultiReader(strings.NewReader("foo"), iotest.ErrReader(errRead)))
+	if !s.Scan() {
+		t.Fatal("must find foo")
+	}
+	if s.Scan() {
+		t.Fatal("must stop on error")
+	}
+	if !errors.Is(s.Err(), errRead) {
+		t.Errorf("unexpected error: %v", s.Err())
+	}
+}
@@ -2,7 +2,11 @@ package runetrie
 
 import (
 	"errors"
+	"fmt"
+	"iter"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // ErrConflictEntry is returned when a new entry conflicts with an existing one.
@@ -10,15 +14,37 @@ import (
 // For example, if you add "foo" and then try to add "FOO", it will return this error.
 var ErrConflictEntry = errors.New("conflict entry")
 
+// ConflictError is the detail of ErrConflictEntry, which carries the conflicting strings.
+// It matches ErrConflictEntry with errors.Is.
+type ConflictError[T ~string] struct {
+	Existing T // the string already in the Trie
+	New      T // the string which is being added
+}
+
+func (e *ConflictError[T]) Error() string {
+	return fmt.Sprintf("%v: %q conflicts with %q", ErrConflictEntry, e.New, e.Existing)
+}
+
+func (e *ConflictError[T]) Is(target error) bool {
+	return target == ErrConflictEntry
+}
+
 // Trie is a prefix tree (trie) .
+// The chains of the nodes with a single child are collapsed into a single node labeled with a substring
+// (radix tree, Patricia trie), so it stays small and fast with long and sparse strings such as MIME types and URL prefixes.
 // It is case sensitive by default.
 type Trie[T ~string] struct {
 	i bool // case insensitive
+	e bool // terminal
 	m map[rune]*Trie[T]
+	p string // the rest of the label after the rune to the node, which is case folded in case insensitive mode
 	l struct {
 		max, min int
 	}
 	s T
+
+	r func(existing, added string) string // the conflict resolver, nil for ConflictFail
+	n *normalizer                         // nil if the strings are not normalized
 }
 
 // Must is a helper function to create a new Trie and panic if an error occurs.
@@ -42,6 +68,9 @@ func NewTrie[T ~string](ss ...T) *Trie[T] {
 
 // NewCaseInsensitiveTrie creates a new case insensitive Trie with the given strings.
 // It is useful for searching strings in a case insensitive manner.
+// The runes are matched if they are in the same case folding orbit of unicode.SimpleFold,
+// such as "K", "k" and "\u212A" (Kelvin sign), or "Σ", "σ" and "ς".
+// The full case folding which maps a rune to multiple runes, such as "ß" to "ss", is not supported.
 // It calls Add method to add the strings to the Trie internally.
 func NewCaseInsensitiveTrie[T ~string](ss ...T) (*Trie[T], error) {
 	trie := &Trie[T]{i: true}
@@ -51,163 +80,593 @@ func NewCaseInsensitiveTrie[T ~string](ss ...T) (*Trie[T], error) {
 	return trie, nil
 }
 
+// NewTrieWithOptions creates a new empty Trie with the given options.
+func NewTrieWithOptions[T ~string](opts ...Option) *Trie[T] {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return newTrie[T](&o)
+}
+
 // Add adds a new string to the Trie.
-// Only in case insensitive mode, it will check for conflicts.
-// If a conflict is found, it returns ErrConflictEntry.
-// If the string is already present, it does nothing and returns nil.
-// If the string is not present, it adds it to the Trie and returns nil.
+// The empty string is also a valid entry, which is a prefix of any string.
+// Only in case insensitive mode or with WithNormalization, it will check for conflicts.
+// If conflicts are found, it skips the conflicting strings, adds the others,
+// and returns the *ConflictError of all the conflicts joined by errors.Join.
+// If the Trie has a conflict resolver by WithConflictPolicy or WithConflictResolver,
+// it stores the string chosen by the resolver instead,
+// but the conflict is returned as well if the resolver chooses neither of them.
+// If the string is already present, it does nothing.
+// If the string is not present, it adds it to the Trie, splitting the labels of the nodes as needed.
 func (t *Trie[T]) Add(ss ...T) error {
+	var errs []error
 	for _, s := range ss {
-		if t.l.min == 0 || t.l.min > len(s) {
-			t.l.min = len(s)
+		lo, hi := foldedLen(string(s), t.i)
+		t.growBounds(lo, hi)
+
+		key := t.key(s)
+		tree, off := t, 0 // off is the byte offset in the label of tree
+		for i := 0; i < len(key); {
+			c, size := utf8.DecodeRuneInString(key[i:])
+			i += size
+			l, h := foldedWidths(c, t.i)
+			lo, hi = lo-l, hi-h
+
+			if off < len(tree.p) {
+				r, n := utf8.DecodeRuneInString(tree.p[off:])
+				if sameRune(r, c, t.i) {
+					off += n
+					continue
+				}
+				tree.split(off, t.i)
+			}
+
+			leaf, ok := tree.m[c]
+			if !ok {
+				// the rest of the string is the label of the new node
+				leaf = &Trie[T]{i: true, p: foldString(key[i:], t.i)}
+				tree.link(c, leaf, t.i)
+				leaf.growBounds(lo, hi)
+				tree, off = leaf, len(leaf.p)
+				break
+			}
+			leaf.growBounds(lo, hi)
+			tree, off = leaf, 0
 		}
-		if t.l.max < len(s) {
-			t.l.max = len(s)
+		if off < len(tree.p) {
+			tree.split(off, t.i)
 		}
 
-		tree := t
-		for i, c := range s {
-			if tree.m == nil {
-				tree.m = map[rune]*Trie[T]{}
+		if tree.e && tree.s != s {
+			if t.r == nil {
+				errs = append(errs, &ConflictError[T]{Existing: tree.s, New: s})
+				continue
 			}
-			if leaf, ok := tree.m[c]; ok {
-				if leaf.l.min == 0 || leaf.l.min > len(s[i+1:]) {
-					leaf.l.min = len(s[i+1:])
-				}
-				if leaf.l.max < len(s[i+1:]) {
-					leaf.l.max = len(s[i+1:])
-				}
-				tree = leaf
-			} else {
-				leaf := &Trie[T]{i: true}
-				leaf.l.min = len(s[i+1:])
-				leaf.l.max = len(s[i+1:])
-				if t.i {
-					tree.m[c] = leaf
-					if unicode.IsLower(c) {
-						tree.m[unicode.ToUpper(c)] = leaf
-					} else if unicode.IsUpper(c) {
-						tree.m[unicode.ToLower(c)] = leaf
-					}
-				} else {
-					tree.m[c] = leaf
-				}
-				tree = leaf
+			resolved := T(t.r(string(tree.s), string(s)))
+			if resolved != tree.s && resolved != s {
+				// the resolver must choose either of them, not to store a string which is not added
+				errs = append(errs, &ConflictError[T]{Existing: tree.s, New: s})
+				continue
 			}
-		}
-		if tree.s != "" && tree.s != s {
-			return ErrConflictEntry
+			s = resolved
 		}
 		tree.s = s
+		tree.e = true
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
-// MatchAny checks if any of the strings in the Trie match the given string.
-// It returns true if there is a match, false otherwise.
-func (t *Trie[T]) MatchAny(s T) bool {
+// link adds the child node by the given rune.
+// In case insensitive mode, all the runes in the case folding orbit are registered as the aliases.
+func (t *Trie[T]) link(c rune, leaf *Trie[T], caseInsensitive bool) {
 	if t.m == nil {
-		return false
+		t.m = map[rune]*Trie[T]{}
+	}
+	t.m[c] = leaf
+	if caseInsensitive {
+		for r := unicode.SimpleFold(c); r != c; r = unicode.SimpleFold(r) {
+			t.m[r] = leaf
+		}
 	}
+}
 
-	tree := t
-	for i, c := range s {
-		if len(s[i:]) < tree.l.min || tree.l.max < len(s[i:]) {
-			return false
+// split splits the label of the node at the byte offset off,
+// and moves the rest of the label and the contents of the node to a new child.
+func (t *Trie[T]) split(off int, caseInsensitive bool) {
+	c, size := utf8.DecodeRuneInString(t.p[off:])
+	child := &Trie[T]{i: true, m: t.m, p: t.p[off+size:], s: t.s, e: t.e}
+	child.resetBounds(caseInsensitive)
+
+	var zero T
+	t.m, t.p, t.s, t.e = nil, t.p[:off], zero, false
+	t.link(c, child, caseInsensitive)
+}
+
+// merge joins the node and its only child into one node, if the node is not terminal.
+// It is the reverse of split, which keeps the Trie in the same shape as built only by Add.
+func (t *Trie[T]) merge(caseInsensitive bool) {
+	if t.e || len(t.m) == 0 {
+		return
+	}
+
+	var c rune
+	var child *Trie[T]
+	for r, leaf := range t.m {
+		if child != nil && leaf != child {
+			return
 		}
+		c, child = r, leaf
+	}
+	if caseInsensitive {
+		c = foldRune(c)
+	}
+	t.m, t.p, t.s, t.e = child.m, t.p+string(c)+child.p, child.s, child.e
+}
 
-		if leaf, ok := tree.m[c]; ok {
-			tree = leaf
-			if tree.m == nil {
+// AddAtomic adds new strings to the Trie like Add, but it adds either all of them or none of them.
+// If conflicts are found, with the strings in the Trie or with each other,
+// it returns the *ConflictError of all the conflicts joined by errors.Join and the Trie is not modified.
+// If the Trie has a conflict resolver, only the conflicts which the resolver does not resolve are returned.
+// The resolver is called for the conflicts in advance to find them, so it should be deterministic.
+func (t *Trie[T]) AddAtomic(ss ...T) error {
+	if t.i || t.n != nil {
+		var errs []error
+		batch := &Trie[T]{i: t.i, n: t.n}
+		for _, s := range ss {
+			// the batch has the strings which will be stored after the preceding ones are added
+			leaf, inBatch := batch.lookup(s), true
+			if leaf == nil || !leaf.e {
+				leaf, inBatch = t.lookup(s), false
+			}
+			if leaf == nil || !leaf.e || leaf.s == s {
+				_ = batch.Add(s)
+				continue
+			}
+			if t.r == nil {
+				errs = append(errs, &ConflictError[T]{Existing: leaf.s, New: s})
+				continue
+			}
+
+			resolved := T(t.r(string(leaf.s), string(s)))
+			if resolved != leaf.s && resolved != s {
+				errs = append(errs, &ConflictError[T]{Existing: leaf.s, New: s})
+				continue
+			}
+			if inBatch {
+				leaf.s = resolved
+			} else {
+				_ = batch.Add(resolved)
+			}
+		}
+		if len(errs) != 0 {
+			return errors.Join(errs...)
+		}
+	}
+	return t.Add(ss...)
+}
+
+// Remove removes the given strings from the Trie.
+// In case insensitive mode, a string removes the entry it matches regardless of its case.
+// The nodes which no longer lead to any string are pruned, and the nodes left with a single child are merged.
+// It returns the number of strings actually removed.
+func (t *Trie[T]) Remove(ss ...T) (removed int) {
+	var path []*Trie[T]
+	var runes []rune
+	for _, s := range ss {
+		path, runes = path[:0], runes[:0]
+
+		key := t.key(s)
+		tree, off := t, 0
+		for i := 0; i < len(key); {
+			c, size := utf8.DecodeRuneInString(key[i:])
+			i += size
+
+			leaf, o := tree.next(c, off, t.i)
+			if leaf == nil {
+				tree = nil
 				break
 			}
-		} else {
-			return false
+			if leaf != tree {
+				path = append(path, tree)
+				runes = append(runes, c)
+			}
+			tree, off = leaf, o
+		}
+		if tree == nil || off != len(tree.p) || !tree.e {
+			continue
+		}
+
+		var zero T
+		tree.s = zero
+		tree.e = false
+		removed++
+
+		for i := len(path) - 1; i >= 0; i-- {
+			parent, c := path[i], runes[i]
+			if tree.isEmpty() {
+				// drop the node with its case folded aliases, if any
+				delete(parent.m, c)
+				for r := unicode.SimpleFold(c); r != c; r = unicode.SimpleFold(r) {
+					if parent.m[r] == tree {
+						delete(parent.m, r)
+					}
+				}
+				if len(parent.m) == 0 {
+					parent.m = nil
+				}
+			} else {
+				tree.merge(t.i)
+				tree.resetBounds(t.i)
+			}
+			tree = parent
 		}
+		t.resetBounds(t.i)
 	}
+	return removed
+}
 
-	return tree.s != ""
+// isEmpty reports whether the node neither terminates a string nor has any children.
+func (t *Trie[T]) isEmpty() bool {
+	return !t.e && t.m == nil
+}
+
+// growBounds extends the length bounds of the node to include a remaining string of lo to hi bytes.
+// In case insensitive mode, the byte length of the input differs from the string by the case folding,
+// so the bounds are computed by foldedLen.
+func (t *Trie[T]) growBounds(lo, hi int) {
+	if t.isEmpty() {
+		t.l.min = lo
+		t.l.max = hi
+		return
+	}
+	if t.l.min > lo {
+		t.l.min = lo
+	}
+	if t.l.max < hi {
+		t.l.max = hi
+	}
+}
+
+// resetBounds recomputes the length bounds of the node from its label and the bounds of its children.
+func (t *Trie[T]) resetBounds(caseInsensitive bool) {
+	t.l.min, t.l.max = 0, 0
+	first := !t.e
+	for c, leaf := range t.m {
+		lo, hi := runeWidths(c)
+		if first || t.l.min > lo+leaf.l.min {
+			t.l.min = lo + leaf.l.min
+		}
+		if t.l.max < hi+leaf.l.max {
+			t.l.max = hi + leaf.l.max
+		}
+		first = false
+	}
+
+	lo, hi := foldedLen(t.p, caseInsensitive)
+	t.l.min += lo
+	t.l.max += hi
+}
+
+// MatchAny checks if any of the strings in the Trie match the given string.
+// It returns true if there is a match, false otherwise.
+func (t *Trie[T]) MatchAny(s T) bool {
+	return t.find(s) != nil
 }
 
 // MatchAnyPrefixOf checks if any of the strings in the Trie match any prefix of the given string.
 // It returns true if there is a match, false otherwise.
 func (t *Trie[T]) MatchAnyPrefixOf(s T) bool {
-	if len(s) < t.l.min {
-		return false
+	leaf, _ := t.shortestPrefix(s)
+	return leaf != nil
+}
+
+// MatchPrefixOf checks if the given string's prefix matches any of the strings in the Trie.
+// It returns the shortest matched string and true if there is a match, or an empty string and false otherwise.
+func (t *Trie[T]) MatchPrefixOf(s T) (T, bool) {
+	if leaf, _ := t.shortestPrefix(s); leaf != nil {
+		return leaf.s, true
 	}
-	if len(s) > t.l.max {
-		s = s[:t.l.max]
+
+	var zero T
+	return zero, false
+}
+
+// MatchPrefixOf checks if the given string's prefix matches any of the strings in the Trie.
+// It returns the longest matched string and true if there is a match, or an empty string and false otherwise.
+func (t *Trie[T]) LongestMatchPrefixOf(s T) (T, bool) {
+	if leaf, _ := t.longestPrefix(s); leaf != nil {
+		return leaf.s, true
+	}
+
+	var zero T
+	return zero, false
+}
+
+// find returns the terminal node that exactly matches the given string, or nil if there is none.
+func (t *Trie[T]) find(s T) *Trie[T] {
+	if t.n != nil {
+		var result *Trie[T]
+		t.normalized(string(s), func(end int, leaf *Trie[T]) bool {
+			if end == len(s) {
+				result = leaf
+			}
+			return true
+		})
+		return result
+	}
+
+	if len(s) < t.l.min || t.l.max < len(s) {
+		return nil
 	}
 
 	tree := t
-	for _, c := range s {
-		if leaf, ok := tree.m[c]; ok {
-			if leaf.s != "" {
-				return true
+	for i := 0; i < len(s); {
+		c, size := rune(s[i]), 1
+		if c >= utf8.RuneSelf {
+			c, size = utf8.DecodeRuneInString(string(s[i:]))
+		}
+		i += size
+
+		leaf, ok := tree.m[c]
+		if !ok {
+			return nil
+		}
+		if len(s[i:]) < leaf.l.min || leaf.l.max < len(s[i:]) {
+			return nil
+		}
+		if len(leaf.p) != 0 {
+			n := leaf.matchLabel(string(s[i:]), t.i)
+			if n < 0 {
+				return nil
 			}
-			tree = leaf
-		} else {
-			break
+			i += n
 		}
+		tree = leaf
 	}
-	return tree.s != ""
+
+	if !tree.e {
+		return nil
+	}
+	return tree
 }
 
-// MatchPrefixOf checks if the given string's prefix matches any of the strings in the Trie.
-// It returns the shortest matched string and true if there is a match, or an empty string and false otherwise.
-func (t *Trie[T]) MatchPrefixOf(s T) (T, bool) {
+// lookup returns the node of the given string whether it is terminal or not, or nil if there is none.
+// Unlike find, it does not rely on the bounds.
+func (t *Trie[T]) lookup(s T) *Trie[T] {
+	tree, off := t.walk(t.key(s))
+	if tree == nil || off != len(tree.p) {
+		return nil
+	}
+	return tree
+}
+
+// walk returns the node where the given key ends and the byte offset in its label, or nil if there is none.
+// All the strings under the node start with the key.
+func (t *Trie[T]) walk(key string) (*Trie[T], int) {
+	tree, off := t, 0
+	for _, c := range key {
+		if tree, off = tree.next(c, off, t.i); tree == nil {
+			return nil, 0
+		}
+	}
+	return tree, off
+}
+
+// next returns the node and the byte offset in its label reached from the byte offset off in the label of the node by the given rune,
+// or nil if there is none.
+func (t *Trie[T]) next(c rune, off int, caseInsensitive bool) (*Trie[T], int) {
+	if off < len(t.p) {
+		r, size := utf8.DecodeRuneInString(t.p[off:])
+		if !sameRune(r, c, caseInsensitive) {
+			return nil, 0
+		}
+		return t, off + size
+	}
+
+	leaf, ok := t.m[c]
+	if !ok {
+		return nil, 0
+	}
+	return leaf, 0
+}
+
+// matchLabel returns the byte length of the head of s which matches the label of the node, or -1 if it does not match.
+func (t *Trie[T]) matchLabel(s string, caseInsensitive bool) int {
+	if !caseInsensitive && strings.HasPrefix(s, t.p) {
+		return len(t.p)
+	}
+
+	i := 0
+	for j := 0; j < len(t.p); {
+		if i == len(s) {
+			return -1
+		}
+
+		r, n := rune(t.p[j]), 1
+		if r >= utf8.RuneSelf {
+			r, n = utf8.DecodeRuneInString(t.p[j:])
+		}
+		c, size := rune(s[i]), 1
+		if c >= utf8.RuneSelf {
+			c, size = utf8.DecodeRuneInString(s[i:])
+		}
+		if !sameRune(r, c, caseInsensitive) {
+			return -1
+		}
+		i, j = i+size, j+n
+	}
+	return i
+}
+
+// sameRune reports whether the rune in a label matches the given rune.
+// The labels are case folded in case insensitive mode.
+func sameRune(r, c rune, caseInsensitive bool) bool {
+	return r == c || caseInsensitive && r == foldRune(c)
+}
+
+// key returns the string to be stored in the Trie, which is normalized if the Trie has a normalizer.
+func (t *Trie[T]) key(s T) string {
+	if t.n != nil {
+		return t.n.key(string(s))
+	}
+	return string(s)
+}
+
+// shortestPrefix returns the terminal node of the shortest string in the Trie which is a prefix of the given string,
+// and the byte offset in s where the match ends. It returns nil if there is no match.
+func (t *Trie[T]) shortestPrefix(s T) (*Trie[T], int) {
+	if t.n != nil {
+		var result *Trie[T]
+		var end int
+		t.normalized(string(s), func(i int, leaf *Trie[T]) bool {
+			result, end = leaf, i
+			return false
+		})
+		return result, end
+	}
+
 	if len(s) < t.l.min {
-		var zero T
-		return zero, false
+		return nil, 0
 	}
 	if len(s) > t.l.max {
 		s = s[:t.l.max]
 	}
 
+	if t.e {
+		return t, 0
+	}
+
 	tree := t
-	for _, c := range s {
-		if leaf, ok := tree.m[c]; ok {
-			if leaf.s != "" {
-				return leaf.s, true
-			}
-			tree = leaf
-		} else {
+	for i := 0; i < len(s); {
+		c, size := rune(s[i]), 1
+		if c >= utf8.RuneSelf {
+			c, size = utf8.DecodeRuneInString(string(s[i:]))
+		}
+		i += size
+
+		leaf, ok := tree.m[c]
+		if !ok {
 			break
 		}
+		if len(leaf.p) != 0 {
+			n := leaf.matchLabel(string(s[i:]), t.i)
+			if n < 0 {
+				break
+			}
+			i += n
+		}
+		if leaf.e {
+			return leaf, i
+		}
+		tree = leaf
 	}
-
-	var zero T
-	return zero, false
+	return nil, 0
 }
 
-// MatchPrefixOf checks if the given string's prefix matches any of the strings in the Trie.
-// It returns the longest matched string and true if there is a match, or an empty string and false otherwise.
-func (t *Trie[T]) LongestMatchPrefixOf(s T) (T, bool) {
+// longestPrefix returns the terminal node of the longest string in the Trie which is a prefix of the given string,
+// and the byte offset in s where the match ends. It returns nil if there is no match.
+func (t *Trie[T]) longestPrefix(s T) (*Trie[T], int) {
+	if t.n != nil {
+		var result *Trie[T]
+		var end int
+		t.normalized(string(s), func(i int, leaf *Trie[T]) bool {
+			result, end = leaf, i
+			return true
+		})
+		return result, end
+	}
+
 	if len(s) < t.l.min {
-		var zero T
-		return zero, false
+		return nil, 0
 	}
 	if len(s) > t.l.max {
 		s = s[:t.l.max]
 	}
 
-	var result T
-	matched := false
+	var result *Trie[T]
+	if t.e {
+		result = t
+	}
+	end := 0
 	tree := t
-	for _, c := range s {
-		if leaf, ok := tree.m[c]; ok {
-			if leaf.s != "" {
-				result = leaf.s
-				matched = true
+	for i := 0; i < len(s); {
+		c, size := rune(s[i]), 1
+		if c >= utf8.RuneSelf {
+			c, size = utf8.DecodeRuneInString(string(s[i:]))
+		}
+		i += size
+
+		leaf, ok := tree.m[c]
+		if !ok {
+			break
+		}
+		if len(leaf.p) != 0 {
+			n := leaf.matchLabel(string(s[i:]), t.i)
+			if n < 0 {
+				break
+			}
+			i += n
+		}
+		if leaf.e {
+			result, end = leaf, i
+		}
+
+		tree = leaf
+		if tree.m == nil {
+			break
+		}
+	}
+	return result, end
+}
+
+// prefixes returns an iterator over the terminal nodes of all the strings in the Trie which are a prefix of the given string,
+// from the shortest to the longest, together with the byte offset in s where each match ends.
+func (t *Trie[T]) prefixes(s T) iter.Seq2[int, *Trie[T]] {
+	return func(yield func(int, *Trie[T]) bool) {
+		if t.n != nil {
+			t.normalized(string(s), yield)
+			return
+		}
+
+		if len(s) < t.l.min {
+			return
+		}
+		if len(s) > t.l.max {
+			s = s[:t.l.max]
+		}
+
+		if t.e && !yield(0, t) {
+			return
+		}
+
+		tree := t
+		for i := 0; i < len(s); {
+			c, size := rune(s[i]), 1
+			if c >= utf8.RuneSelf {
+				c, size = utf8.DecodeRuneInString(string(s[i:]))
+			}
+			i += size
+
+			leaf, ok := tree.m[c]
+			if !ok {
+				return
+			}
+			if len(leaf.p) != 0 {
+				n := leaf.matchLabel(string(s[i:]), t.i)
+				if n < 0 {
+					return
+				}
+				i += n
+			}
+			if leaf.e && !yield(i, leaf) {
+				return
 			}
 
 			tree = leaf
 			if tree.m == nil {
-				break
+				return
 			}
-		} else {
-			break
 		}
 	}
-	return result, matched
 }
@@ -2,6 +2,10 @@ package runetrie_test
 
 import (
 	"errors"
+	"math/rand"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -44,6 +48,401 @@ func TestAdd(t *testing.T) {
 	}
 }
 
+func TestAdd_ConflictError(t *testing.T) {
+	tr := runetrie.Must(runetrie.NewCaseInsensitiveTrie("foo"))
+	err := tr.Add("FOO", "bar", "Bar", "baz")
+	if !errors.Is(err, runetrie.ErrConflictEntry) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := conflictsOf(t, err)
+	want := []runetrie.ConflictError[string]{{Existing: "foo", New: "FOO"}, {Existing: "bar", New: "Bar"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected conflicts.\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"bar", "baz", "foo"}, slices.Collect(tr.All())); diff != "" {
+		t.Errorf("the non-conflicting strings must be added.\n%s", diff)
+	}
+}
+
+func TestAddAtomic(t *testing.T) {
+	tests := []struct {
+		name          string
+		set           []string
+		add           []string
+		resolver      func(existing, added string) string
+		wantConflicts []runetrie.ConflictError[string]
+		want          []string
+	}{
+		{
+			name: "NoConflict",
+			set:  []string{"foo"},
+			add:  []string{"foo", "bar", "baz"},
+			want: []string{"bar", "baz", "foo"},
+		},
+		{
+			name:          "ConflictWithExisting",
+			set:           []string{"foo"},
+			add:           []string{"bar", "FOO"},
+			wantConflicts: []runetrie.ConflictError[string]{{Existing: "foo", New: "FOO"}},
+			want:          []string{"foo"},
+		},
+		{
+			name:          "ConflictInBatch",
+			set:           []string{"foo"},
+			add:           []string{"bar", "BAR", "baz", "Baz", "bAz"},
+			wantConflicts: []runetrie.ConflictError[string]{{Existing: "bar", New: "BAR"}, {Existing: "baz", New: "Baz"}, {Existing: "baz", New: "bAz"}},
+			want:          []string{"foo"},
+		},
+		{
+			name:     "ResolverReplace",
+			set:      []string{"foo"},
+			add:      []string{"bar", "FOO", "Bar", "BAR"},
+			resolver: func(_, added string) string { return added },
+			want:     []string{"BAR", "FOO"},
+		},
+		{
+			name:          "ResolverOtherString",
+			set:           []string{"foo"},
+			add:           []string{"bar", "FOO"},
+			resolver:      func(_, _ string) string { return "zzz" },
+			wantConflicts: []runetrie.ConflictError[string]{{Existing: "foo", New: "FOO"}},
+			want:          []string{"foo"},
+		},
+		{
+			name:          "ResolverOtherStringInBatch",
+			set:           []string{"foo"},
+			add:           []string{"bar", "BAR"},
+			resolver:      func(_, _ string) string { return "zzz" },
+			wantConflicts: []runetrie.ConflictError[string]{{Existing: "bar", New: "BAR"}},
+			want:          []string{"foo"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrieWithOptions[string](runetrie.WithCaseInsensitive(), runetrie.WithConflictResolver(tt.resolver))
+			if tt.resolver == nil {
+				tr = runetrie.NewTrieWithOptions[string](runetrie.WithCaseInsensitive())
+			}
+			if err := tr.Add(tt.set...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err := tr.AddAtomic(tt.add...)
+
+			if diff := cmp.Diff(tt.wantConflicts, conflictsOf(t, err)); diff != "" {
+				t.Errorf("unexpected conflicts.\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.want, slices.Collect(tr.All())); diff != "" {
+				t.Errorf("unexpected strings.\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewTrieWithOptions_ConflictPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []runetrie.Option
+		add           []string
+		wantConflicts []runetrie.ConflictError[string]
+		want          []string
+	}{
+		{
+			name:          "Default",
+			opts:          []runetrie.Option{runetrie.WithCaseInsensitive()},
+			add:           []string{"foo", "FOO", "Bar", "bar"},
+			wantConflicts: []runetrie.ConflictError[string]{{Existing: "foo", New: "FOO"}, {Existing: "Bar", New: "bar"}},
+			want:          []string{"Bar", "foo"},
+		},
+		{
+			name:          "Fail",
+			opts:          []runetrie.Option{runetrie.WithCaseInsensitive(), runetrie.WithConflictPolicy(runetrie.ConflictFail)},
+			add:           []string{"foo", "FOO"},
+			wantConflicts: []runetrie.ConflictError[string]{{Existing: "foo", New: "FOO"}},
+			want:          []string{"foo"},
+		},
+		{
+			name: "KeepFirst",
+			opts: []runetrie.Option{runetrie.WithCaseInsensitive(), runetrie.WithConflictPolicy(runetrie.ConflictKeepFirst)},
+			add:  []string{"foo", "FOO", "Bar", "bar"},
+			want: []string{"Bar", "foo"},
+		},
+		{
+			name: "Replace",
+			opts: []runetrie.Option{runetrie.WithCaseInsensitive(), runetrie.WithConflictPolicy(runetrie.ConflictReplace)},
+			add:  []string{"foo", "FOO", "Bar", "bar"},
+			want: []string{"bar", "FOO"},
+		},
+		{
+			name: "Resolver",
+			opts: []runetrie.Option{runetrie.WithCaseInsensitive(), runetrie.WithConflictResolver(func(existing, added string) string {
+				return min(existing, added)
+			})},
+			add:  []string{"foo", "FOO", "Bar", "bar"},
+			want: []string{"Bar", "FOO"},
+		},
+		{
+			name: "ResolverOtherString",
+			opts: []runetrie.Option{runetrie.WithCaseInsensitive(), runetrie.WithConflictResolver(func(existing, added string) string {
+				return "zzz"
+			})},
+			add:           []string{"foo", "FOO"},
+			wantConflicts: []runetrie.ConflictError[string]{{Existing: "foo", New: "FOO"}},
+			want:          []string{"foo"},
+		},
+		{
+			name: "CaseSensitive",
+			opts: []runetrie.Option{runetrie.WithConflictPolicy(runetrie.ConflictKeepFirst)},
+			add:  []string{"foo", "FOO"},
+			want: []string{"FOO", "foo"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrieWithOptions[string](tt.opts...)
+			err := tr.Add(tt.add...)
+			if diff := cmp.Diff(tt.wantConflicts, conflictsOf(t, err)); diff != "" {
+				t.Errorf("unexpected conflicts.\n%s", diff)
+			}
+
+			got := slices.Collect(tr.All())
+			t.Logf("got: %s", pp.Sprint(got))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unexpected strings.\n%s", diff)
+			}
+		})
+	}
+}
+
+// conflictsOf returns the ConflictErrors joined in the given error.
+func conflictsOf(t *testing.T, err error) []runetrie.ConflictError[string] {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+
+	var conflicts []runetrie.ConflictError[string]
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var conflict *runetrie.ConflictError[string]
+		if !errors.As(err, &conflict) {
+			t.Fatalf("must be ConflictError: %v", err)
+		}
+		conflicts = append(conflicts, *conflict)
+	}
+	return conflicts
+}
+
+func TestRemove(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     []string
+		remove  []string
+		removed int
+		want    []string
+	}{
+		{
+			name:    "Empty",
+			set:     []string{},
+			remove:  []string{"foo"},
+			removed: 0,
+			want:    []string{},
+		},
+		{
+			name:    "NotFound",
+			set:     []string{"foo", "foobar"},
+			remove:  []string{"fo", "foob", "bar"},
+			removed: 0,
+			want:    []string{"foo", "foobar"},
+		},
+		{
+			name:    "All",
+			set:     []string{"foo", "foobar", "bar"},
+			remove:  []string{"foo", "foobar", "bar"},
+			removed: 3,
+			want:    []string{},
+		},
+		{
+			name:    "Leaf",
+			set:     []string{"A", "AA", "AAA", "ABC"},
+			remove:  []string{"AAA", "ABC"},
+			removed: 2,
+			want:    []string{"A", "AA"},
+		},
+		{
+			name:    "Inner",
+			set:     []string{"A", "AA", "AAA", "ABC"},
+			remove:  []string{"A", "AA"},
+			removed: 2,
+			want:    []string{"AAA", "ABC"},
+		},
+		{
+			name:    "Twice",
+			set:     []string{"A", "AA"},
+			remove:  []string{"AA", "AA"},
+			removed: 1,
+			want:    []string{"A"},
+		},
+		{
+			name:    "EmptyEntry",
+			set:     []string{"", "A"},
+			remove:  []string{""},
+			removed: 1,
+			want:    []string{"A"},
+		},
+		{
+			name:    "MultiByte",
+			set:     []string{"あいう", "あい", "あえ"},
+			remove:  []string{"あいう", "あえ"},
+			removed: 2,
+			want:    []string{"あい"},
+		},
+		{
+			name:    "MergeEdge",
+			set:     []string{"application/json", "application/javascript", "app"},
+			remove:  []string{"application/javascript", "app"},
+			removed: 2,
+			want:    []string{"application/json"},
+		},
+		{
+			name:    "MidEdge",
+			set:     []string{"application/json", "app"},
+			remove:  []string{"appl", "application/js"},
+			removed: 0,
+			want:    []string{"application/json", "app"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tr := runetrie.NewTrie(tt.set...)
+			if removed := tr.Remove(tt.remove...); removed != tt.removed {
+				t.Errorf("Trie.Remove() = %v, want %v", removed, tt.removed)
+			}
+			want := runetrie.NewTrie(tt.want...)
+			if diff := cmp.Diff(want, tr, cmp.Exporter(func(reflect.Type) bool { return true })); diff != "" {
+				t.Errorf("Trie.Remove() must prune the removed nodes.\n%s", diff)
+			}
+			for _, s := range tt.remove {
+				if tr.MatchAny(s) {
+					t.Errorf("Trie.MatchAny(%q) = true after removal", s)
+				}
+			}
+			for _, s := range tt.want {
+				if !tr.MatchAny(s) {
+					t.Errorf("Trie.MatchAny(%q) = false after removal", s)
+				}
+			}
+		})
+	}
+}
+
+func TestRemove_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	alphabet := []rune("abAB√あ")
+	for i := 0; i < 200; i++ {
+		set := make([]string, rnd.Intn(32))
+		for j := range set {
+			set[j] = randomString(rnd, alphabet, 6)
+		}
+		remove, rest := set[:len(set)/2], set[len(set)/2:]
+
+		for _, newTrie := range []func(...string) *runetrie.Trie[string]{runetrie.NewTrie[string], caseInsensitiveTrieOf} {
+			tr := newTrie(set...)
+			tr.Remove(remove...)
+			want := newTrie(slices.DeleteFunc(slices.Clone(rest), func(s string) bool { return !tr.MatchAny(s) })...)
+			if diff := cmp.Diff(want, tr, cmp.Exporter(func(reflect.Type) bool { return true })); diff != "" {
+				t.Fatalf("Trie.Remove(%q) from %q must be the same as the Trie built from the rest.\n%s", remove, set, diff)
+			}
+		}
+	}
+}
+
+func TestRemove_CaseInsensitive(t *testing.T) {
+	tr := runetrie.Must(runetrie.NewCaseInsensitiveTrie("Foo", "FooBar"))
+	if removed := tr.Remove("fOObAR"); removed != 1 {
+		t.Errorf("Trie.Remove() = %v, want 1", removed)
+	}
+	want := runetrie.Must(runetrie.NewCaseInsensitiveTrie("Foo"))
+	if diff := cmp.Diff(want, tr, cmp.Exporter(func(reflect.Type) bool { return true })); diff != "" {
+		t.Errorf("Trie.Remove() must prune the removed nodes including the aliases.\n%s", diff)
+	}
+	if err := tr.Add("FOOBAR"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got, _ := tr.LongestMatchPrefixOf("foobarbaz"); got != "FOOBAR" {
+		t.Errorf("Trie.LongestMatchPrefixOf() = %v, want FOOBAR", got)
+	}
+}
+
+func TestRemove_CaseInsensitiveFoldOrbit(t *testing.T) {
+	tr := runetrie.Must(runetrie.NewCaseInsensitiveTrie("Foo", "FooBark"))
+	if removed := tr.Remove("fOObAR\u212a"); removed != 1 {
+		t.Errorf("Trie.Remove() = %v, want 1", removed)
+	}
+	want := runetrie.Must(runetrie.NewCaseInsensitiveTrie("Foo"))
+	if diff := cmp.Diff(want, tr, cmp.Exporter(func(reflect.Type) bool { return true })); diff != "" {
+		t.Errorf("Trie.Remove() must prune the removed nodes including the aliases in the case folding orbit.\n%s", diff)
+	}
+}
+
+func Test_Trie_PathCompression(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     []string
+		targets []string
+	}{
+		{
+			name:    "Prefixes",
+			set:     []string{"A", "AA", "AAA", "ABCA"},
+			targets: []string{"", "A", "AA", "AAC", "AAAA", "AB", "ABC", "ABCABC", "abca"},
+		},
+		{
+			name:    "SplitEdge",
+			set:     []string{"application/json", "application/javascript", "application/xml", "app"},
+			targets: []string{"app", "appl", "application/", "application/json; charset=utf-8", "application/jsonp", "application/java", "APPLICATION/XML"},
+		},
+		{
+			name:    "MultiByte",
+			set:     []string{"東", "東京", "東京都", "京都", "éa", "aé"},
+			targets: []string{"東京都庁", "東京タワー", "京", "京都", "éa", "aé", "ÉA"},
+		},
+		{
+			name:    "CaseFolding",
+			set:     []string{"Foo", "BAR", "ÀB", "ΣΑΣ"},
+			targets: []string{"foo", "FOOBAR", "bar", "àb", "σασ", "ΣΑς"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			assertLookup(t, "Trie", runetrie.NewTrie(tt.set...), prefixOracle{keys: tt.set, equal: func(a, b string) bool { return a == b }}, tt.targets)
+			assertLookup(t, "Trie", caseInsensitiveTrieOf(tt.set...), prefixOracle{keys: tt.set, equal: strings.EqualFold}, tt.targets)
+		})
+	}
+}
+
+func TestTrie_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	alphabet := []rune("abAB√あ")
+	for i := 0; i < 200; i++ {
+		set := make([]string, rnd.Intn(32))
+		for j := range set {
+			set[j] = randomString(rnd, alphabet, 6)
+		}
+		targets := make([]string, 32)
+		for j := range targets {
+			targets[j] = randomString(rnd, alphabet, 8)
+		}
+
+		// the case insensitive ones are checked by TestCaseInsensitive_EqualFold
+		assertLookup(t, "Trie", runetrie.NewTrie(set...), prefixOracle{keys: set, equal: func(a, b string) bool { return a == b }}, targets)
+	}
+}
+
 func Test_Trie_MatchAnyPrefixOf(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -69,6 +468,18 @@ func Test_Trie_MatchAnyPrefixOf(t *testing.T) {
 			target: "",
 			want:   false,
 		},
+		{
+			name:   "EmptyEntryIsPrefixOfEmpty",
+			set:    []string{"", "A"},
+			target: "",
+			want:   true,
+		},
+		{
+			name:   "EmptyEntryIsPrefixOfAnyStrings",
+			set:    []string{"", "A"},
+			target: "B",
+			want:   true,
+		},
 		{
 			name:   "ExactlyMatchA",
 			set:    []string{"A"},
@@ -142,6 +553,12 @@ func Test_Trie_MatchPrefixOf(t *testing.T) {
 			target: "",
 			want:   ret{"", false},
 		},
+		{
+			name:   "EmptyEntryIsShortest",
+			set:    []string{"", "A"},
+			target: "AB",
+			want:   ret{"", true},
+		},
 		{
 			name:   "ExactlyMatchA",
 			set:    []string{"A"},
@@ -215,6 +632,18 @@ func Test_Trie_LongestMatchPrefixOf(t *testing.T) {
 			target: "",
 			want:   ret{"", false},
 		},
+		{
+			name:   "EmptyEntryIsNotLongest",
+			set:    []string{"", "A"},
+			target: "AB",
+			want:   ret{"A", true},
+		},
+		{
+			name:   "EmptyEntryIsFallback",
+			set:    []string{"", "A"},
+			target: "BC",
+			want:   ret{"", true},
+		},
 		{
 			name:   "ExactlyMatchA",
 			set:    []string{"A"},
@@ -278,6 +707,18 @@ func Test_Trie_MatchAny(t *testing.T) {
 			target: "",
 			want:   false,
 		},
+		{
+			name:   "EmptyEntry",
+			set:    []string{"", "A"},
+			target: "",
+			want:   true,
+		},
+		{
+			name:   "EmptyEntryIsNotAnyStrings",
+			set:    []string{"", "A"},
+			target: "B",
+			want:   false,
+		},
 		{
 			name:   "ExactlyMatchA",
 			set:    []string{"A"},
@@ -320,6 +761,18 @@ func Test_Trie_MatchAny(t *testing.T) {
 			target: "ABCD",
 			want:   false,
 		},
+		{
+			name:   "LongerThanLeaf",
+			set:    []string{"A", "BCD"},
+			target: "AB",
+			want:   false,
+		},
+		{
+			name:   "MultiByte",
+			set:    []string{"éa", "aé"},
+			target: "éa",
+			want:   true,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -358,6 +811,18 @@ func Test_CaseInsensitiveTrie_MatchAnyPrefixOf(t *testing.T) {
 			target: "",
 			want:   false,
 		},
+		{
+			name:   "EmptyEntryIsPrefixOfEmpty",
+			set:    []string{"", "A"},
+			target: "",
+			want:   true,
+		},
+		{
+			name:   "EmptyEntryIsPrefixOfAnyStrings",
+			set:    []string{"", "A"},
+			target: "B",
+			want:   true,
+		},
 		{
 			name:   "ExactlyMatchA",
 			set:    []string{"A"},
@@ -388,6 +853,42 @@ func Test_CaseInsensitiveTrie_MatchAnyPrefixOf(t *testing.T) {
 			target: "abcabc",
 			want:   true,
 		},
+		{
+			name:   "FoldOrbitKelvinSign",
+			set:    []string{"kilo", "k"},
+			target: "\u212aelvin",
+			want:   true,
+		},
+		{
+			name:   "FoldOrbitLongS",
+			set:    []string{"s", "ss"},
+			target: "\u017fun",
+			want:   true,
+		},
+		{
+			name:   "FoldOrbitFinalSigma",
+			set:    []string{"ΣΑΣ"},
+			target: "σας",
+			want:   true,
+		},
+		{
+			name:   "FoldOrbitTitleCaseDigraph",
+			set:    []string{"\u01c5ungla"},
+			target: "\u01c4UNGLA",
+			want:   true,
+		},
+		{
+			name:   "FoldWidthKelvinSign",
+			set:    []string{"kelvin"},
+			target: "\u212aelvin",
+			want:   true,
+		},
+		{
+			name:   "FoldWidthLongS",
+			set:    []string{"\u017f\u017f"},
+			target: "ss",
+			want:   true,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -431,6 +932,12 @@ func Test_CaseInsensitiveTrie_MatchPrefixOf(t *testing.T) {
 			target: "",
 			want:   ret{"", false},
 		},
+		{
+			name:   "EmptyEntryIsShortest",
+			set:    []string{"", "A"},
+			target: "AB",
+			want:   ret{"", true},
+		},
 		{
 			name:   "ExactlyMatchA",
 			set:    []string{"A"},
@@ -455,6 +962,42 @@ func Test_CaseInsensitiveTrie_MatchPrefixOf(t *testing.T) {
 			target: "abcabc",
 			want:   ret{"ABCA", true},
 		},
+		{
+			name:   "FoldOrbitKelvinSign",
+			set:    []string{"kilo", "k"},
+			target: "\u212aelvin",
+			want:   ret{"k", true},
+		},
+		{
+			name:   "FoldOrbitLongS",
+			set:    []string{"s", "ss"},
+			target: "\u017fun",
+			want:   ret{"s", true},
+		},
+		{
+			name:   "FoldOrbitFinalSigma",
+			set:    []string{"ΣΑΣ", "ΣΑ"},
+			target: "σας",
+			want:   ret{"ΣΑ", true},
+		},
+		{
+			name:   "FoldOrbitTitleCaseDigraph",
+			set:    []string{"\u01c5ungla"},
+			target: "\u01c4UNGLA",
+			want:   ret{"\u01c5ungla", true},
+		},
+		{
+			name:   "FoldWidthKelvinSign",
+			set:    []string{"kelvin"},
+			target: "\u212aelvin",
+			want:   ret{"kelvin", true},
+		},
+		{
+			name:   "FoldWidthLongS",
+			set:    []string{"\u017f\u017f"},
+			target: "ss",
+			want:   ret{"\u017f\u017f", true},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -498,6 +1041,18 @@ func Test_CaseInsensitiveTrie_LongestMatchPrefixOf(t *testing.T) {
 			target: "",
 			want:   ret{"", false},
 		},
+		{
+			name:   "EmptyEntryIsNotLongest",
+			set:    []string{"", "A"},
+			target: "AB",
+			want:   ret{"A", true},
+		},
+		{
+			name:   "EmptyEntryIsFallback",
+			set:    []string{"", "A"},
+			target: "BC",
+			want:   ret{"", true},
+		},
 		{
 			name:   "ExactlyMatchA",
 			set:    []string{"A"},
@@ -522,6 +1077,42 @@ func Test_CaseInsensitiveTrie_LongestMatchPrefixOf(t *testing.T) {
 			target: "abcabc",
 			want:   ret{"ABCA", true},
 		},
+		{
+			name:   "FoldOrbitKelvinSign",
+			set:    []string{"kilo", "k"},
+			target: "\u212aelvin",
+			want:   ret{"k", true},
+		},
+		{
+			name:   "FoldOrbitLongS",
+			set:    []string{"s", "ss"},
+			target: "\u017fun",
+			want:   ret{"s", true},
+		},
+		{
+			name:   "FoldOrbitFinalSigma",
+			set:    []string{"ΣΑΣ", "ΣΑ"},
+			target: "σας",
+			want:   ret{"ΣΑΣ", true},
+		},
+		{
+			name:   "FoldOrbitTitleCaseDigraph",
+			set:    []string{"\u01c5ungla"},
+			target: "\u01c4UNGLA",
+			want:   ret{"\u01c5ungla", true},
+		},
+		{
+			name:   "FoldWidthKelvinSign",
+			set:    []string{"k", "kk"},
+			target: "\u212a\u212a",
+			want:   ret{"kk", true},
+		},
+		{
+			name:   "FoldWidthLongS",
+			set:    []string{"s", "ss"},
+			target: "\u017f\u017f",
+			want:   ret{"ss", true},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -561,6 +1152,18 @@ func Test_CaseInsensitiveTrie_MatchAny(t *testing.T) {
 			target: "",
 			want:   false,
 		},
+		{
+			name:   "EmptyEntry",
+			set:    []string{"", "A"},
+			target: "",
+			want:   true,
+		},
+		{
+			name:   "EmptyEntryIsNotAnyStrings",
+			set:    []string{"", "A"},
+			target: "B",
+			want:   false,
+		},
 		{
 			name:   "ExactlyMatchA",
 			set:    []string{"A"},
@@ -603,6 +1206,42 @@ func Test_CaseInsensitiveTrie_MatchAny(t *testing.T) {
 			target: "ABCD",
 			want:   false,
 		},
+		{
+			name:   "FoldOrbitKelvinSign",
+			set:    []string{"k", "kilo"},
+			target: "\u212a",
+			want:   true,
+		},
+		{
+			name:   "FoldOrbitLongS",
+			set:    []string{"s", "ss"},
+			target: "\u017f",
+			want:   true,
+		},
+		{
+			name:   "FoldOrbitFinalSigma",
+			set:    []string{"ΣΑΣ"},
+			target: "σας",
+			want:   true,
+		},
+		{
+			name:   "FoldOrbitTitleCaseDigraph",
+			set:    []string{"\u01c5ungla"},
+			target: "\u01c4UNGLA",
+			want:   true,
+		},
+		{
+			name:   "FoldWidthKelvinSign",
+			set:    []string{"k"},
+			target: "\u212a",
+			want:   true,
+		},
+		{
+			name:   "FoldWidthLongS",
+			set:    []string{"\u017f"},
+			target: "S",
+			want:   true,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -0,0 +1,94 @@
+package runetrie
+
+// TrieMap is a prefix tree (trie) which associates a value with each string.
+// It is case sensitive by default.
+type TrieMap[K ~string, V any] struct {
+	t *Trie[K]
+	v map[K]V
+}
+
+// NewTrieMap creates a new case sensitive TrieMap with the given key/value pairs.
+// It calls Set method to add the pairs to the TrieMap internally.
+func NewTrieMap[K ~string, V any](m map[K]V) *TrieMap[K, V] {
+	tm := &TrieMap[K, V]{t: NewTrie[K](), v: make(map[K]V, len(m))}
+	for k, v := range m {
+		_ = tm.Set(k, v)
+	}
+	return tm
+}
+
+// NewCaseInsensitiveTrieMap creates a new case insensitive TrieMap with the given key/value pairs.
+// It calls Set method to add the pairs to the TrieMap internally.
+// Like NewCaseInsensitiveTrie, it returns ErrConflictEntry if the keys conflict with each other.
+func NewCaseInsensitiveTrieMap[K ~string, V any](m map[K]V) (*TrieMap[K, V], error) {
+	t, err := NewCaseInsensitiveTrie[K]()
+	if err != nil {
+		return nil, err
+	}
+
+	tm := &TrieMap[K, V]{t: t, v: make(map[K]V, len(m))}
+	for k, v := range m {
+		if err := tm.Set(k, v); err != nil {
+			return nil, err
+		}
+	}
+	return tm, nil
+}
+
+// Set associates the value with the given key.
+// If the key is already present, its value is replaced.
+// Only in case insensitive mode, it will check for conflicts.
+// If a conflict is found, it returns *ConflictError and the TrieMap is not modified.
+func (tm *TrieMap[K, V]) Set(k K, v V) error {
+	if err := tm.t.Add(k); err != nil {
+		return err
+	}
+	tm.v[k] = v
+	return nil
+}
+
+// Get returns the value associated with the string in the TrieMap which matches the given string.
+// It returns the value and true if there is a match, or the zero value and false otherwise.
+func (tm *TrieMap[K, V]) Get(k K) (V, bool) {
+	if leaf := tm.t.find(k); leaf != nil {
+		return tm.v[leaf.s], true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// ShortestPrefixValue returns the value associated with the shortest string in the TrieMap
+// which is a prefix of the given string.
+// It returns the value and true if there is a match, or the zero value and false otherwise.
+func (tm *TrieMap[K, V]) ShortestPrefixValue(s K) (V, bool) {
+	if leaf, _ := tm.t.shortestPrefix(s); leaf != nil {
+		return tm.v[leaf.s], true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// LongestPrefixValue returns the value associated with the longest string in the TrieMap
+// which is a prefix of the given string.
+// It returns the value and true if there is a match, or the zero value and false otherwise.
+func (tm *TrieMap[K, V]) LongestPrefixValue(s K) (V, bool) {
+	if leaf, _ := tm.t.longestPrefix(s); leaf != nil {
+		return tm.v[leaf.s], true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// PrefixValues returns the values associated with all the strings in the TrieMap
+// which are a prefix of the given string, ordered from the shortest string to the longest one.
+// It returns nil if there is no match.
+func (tm *TrieMap[K, V]) PrefixValues(s K) []V {
+	var values []V
+	for _, leaf := range tm.t.prefixes(s) {
+		values = append(values, tm.v[leaf.s])
+	}
+	return values
+}
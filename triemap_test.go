@@ -0,0 +1,196 @@
+package runetrie_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k0kubun/pp"
+	"github.com/karupanerura/runetrie"
+)
+
+func TestNewCaseInsensitiveTrieMap_Conflict(t *testing.T) {
+	tm, err := runetrie.NewCaseInsensitiveTrieMap(map[string]int{"aA": 1, "aa": 2})
+	if err == nil {
+		t.Fatal("must be error")
+	}
+	if tm != nil {
+		t.Errorf("must be omit trie map: %+v", tm)
+	}
+	if !errors.Is(err, runetrie.ErrConflictEntry) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTrieMap_Set(t *testing.T) {
+	tm := runetrie.NewTrieMap(map[string]int{"foo": 1})
+	if err := tm.Set("foo", 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, ok := tm.Get("foo"); !ok || got != 2 {
+		t.Errorf("TrieMap.Get() = (%v, %v), want (2, true)", got, ok)
+	}
+
+	ci, err := runetrie.NewCaseInsensitiveTrieMap(map[string]int{"foo": 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ci.Set("FOO", 2); !errors.Is(err, runetrie.ErrConflictEntry) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got, ok := ci.Get("FOO"); !ok || got != 1 {
+		t.Errorf("TrieMap.Get() = (%v, %v), want (1, true)", got, ok)
+	}
+}
+
+func Test_TrieMap(t *testing.T) {
+	type ret struct {
+		Value   int
+		Matched bool
+	}
+	set := map[string]int{"A": 1, "AA": 2, "AAA": 3, "ABCA": 4}
+	tests := []struct {
+		name     string
+		target   string
+		get      ret
+		shortest ret
+		longest  ret
+		prefixes []int
+	}{
+		{
+			name:   "Empty",
+			target: "",
+		},
+		{
+			name:     "ExactlyMatchA",
+			target:   "A",
+			get:      ret{1, true},
+			shortest: ret{1, true},
+			longest:  ret{1, true},
+			prefixes: []int{1},
+		},
+		{
+			name:     "ExactlyMatchAAA",
+			target:   "AAA",
+			get:      ret{3, true},
+			shortest: ret{1, true},
+			longest:  ret{3, true},
+			prefixes: []int{1, 2, 3},
+		},
+		{
+			name:     "PrefixMatchAAC",
+			target:   "AAC",
+			shortest: ret{1, true},
+			longest:  ret{2, true},
+			prefixes: []int{1, 2},
+		},
+		{
+			name:     "PrefixMatchABCABC",
+			target:   "ABCABC",
+			shortest: ret{1, true},
+			longest:  ret{4, true},
+			prefixes: []int{1, 4},
+		},
+		{
+			name:   "Mismatch",
+			target: "BCD",
+		},
+		{
+			name:     "CaseSensitive",
+			target:   "abca",
+			shortest: ret{},
+			longest:  ret{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tm := runetrie.NewTrieMap(set)
+			if value, matched := tm.Get(tt.target); cmp.Diff(tt.get, ret{value, matched}) != "" {
+				t.Errorf("TrieMap.Get() = (%v, %v), want %v", value, matched, tt.get)
+				t.Log(pp.Sprint(tm))
+			}
+			if value, matched := tm.ShortestPrefixValue(tt.target); cmp.Diff(tt.shortest, ret{value, matched}) != "" {
+				t.Errorf("TrieMap.ShortestPrefixValue() = (%v, %v), want %v", value, matched, tt.shortest)
+				t.Log(pp.Sprint(tm))
+			}
+			if value, matched := tm.LongestPrefixValue(tt.target); cmp.Diff(tt.longest, ret{value, matched}) != "" {
+				t.Errorf("TrieMap.LongestPrefixValue() = (%v, %v), want %v", value, matched, tt.longest)
+				t.Log(pp.Sprint(tm))
+			}
+			if diff := cmp.Diff(tt.prefixes, tm.PrefixValues(tt.target)); diff != "" {
+				t.Errorf("TrieMap.PrefixValues() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tm))
+			}
+		})
+	}
+}
+
+func Test_CaseInsensitiveTrieMap(t *testing.T) {
+	type ret struct {
+		Value   int
+		Matched bool
+	}
+	set := map[string]int{"A": 1, "AA": 2, "AAA": 3, "ABCA": 4}
+	tests := []struct {
+		name     string
+		target   string
+		get      ret
+		shortest ret
+		longest  ret
+		prefixes []int
+	}{
+		{
+			name:   "Empty",
+			target: "",
+		},
+		{
+			name:     "ExactlyMatchA",
+			target:   "a",
+			get:      ret{1, true},
+			shortest: ret{1, true},
+			longest:  ret{1, true},
+			prefixes: []int{1},
+		},
+		{
+			name:     "ExactlyMatchAAA",
+			target:   "aAa",
+			get:      ret{3, true},
+			shortest: ret{1, true},
+			longest:  ret{3, true},
+			prefixes: []int{1, 2, 3},
+		},
+		{
+			name:     "CaseInsensitive",
+			target:   "abcabc",
+			shortest: ret{1, true},
+			longest:  ret{4, true},
+			prefixes: []int{1, 4},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tm, err := runetrie.NewCaseInsensitiveTrieMap(set)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value, matched := tm.Get(tt.target); cmp.Diff(tt.get, ret{value, matched}) != "" {
+				t.Errorf("TrieMap.Get() = (%v, %v), want %v", value, matched, tt.get)
+				t.Log(pp.Sprint(tm))
+			}
+			if value, matched := tm.ShortestPrefixValue(tt.target); cmp.Diff(tt.shortest, ret{value, matched}) != "" {
+				t.Errorf("TrieMap.ShortestPrefixValue() = (%v, %v), want %v", value, matched, tt.shortest)
+				t.Log(pp.Sprint(tm))
+			}
+			if value, matched := tm.LongestPrefixValue(tt.target); cmp.Diff(tt.longest, ret{value, matched}) != "" {
+				t.Errorf("TrieMap.LongestPrefixValue() = (%v, %v), want %v", value, matched, tt.longest)
+				t.Log(pp.Sprint(tm))
+			}
+			if diff := cmp.Diff(tt.prefixes, tm.PrefixValues(tt.target)); diff != "" {
+				t.Errorf("TrieMap.PrefixValues() mismatch.\n%s", diff)
+				t.Log(pp.Sprint(tm))
+			}
+		})
+	}
+}